@@ -0,0 +1,220 @@
+package wade
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gowade/wade/dom"
+	"github.com/gowade/wade/utils/conv"
+)
+
+const (
+	routeBindTag   = "route"
+	queryBindTag   = "query"
+	defaultBindTag = "default"
+	timeLayoutTag  = "layout"
+)
+
+// FieldError is a binding error for a single struct field
+type FieldError struct {
+	Field  string // name of the struct field
+	Source string // "route" or "query"
+	Param  string // name of the parameter
+	Value  string // the invalid value, empty if the parameter is missing
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf(`%v parameter "%v": %v`, e.Source, e.Param, e.Err)
+	}
+
+	return fmt.Sprintf(`%v parameter "%v": invalid value "%v": %v`, e.Source, e.Param, e.Value, e.Err)
+}
+
+// BindError is returned by Context.Bind when some parameters cannot be bound,
+// it lists the errors for all the fields
+type BindError struct {
+	Errors []*FieldError
+}
+
+func (e *BindError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, ferr := range e.Errors {
+		msgs[i] = ferr.Error()
+	}
+
+	return "bind: " + strings.Join(msgs, "; ")
+}
+
+// Field returns the error for the given struct field, nil if there's none
+func (e *BindError) Field(name string) *FieldError {
+	for _, ferr := range e.Errors {
+		if ferr.Field == name {
+			return ferr
+		}
+	}
+
+	return nil
+}
+
+var errParamRequired = errors.New("required")
+
+// Bind sets the fields of the struct pointed to by dest from the route parameters
+// and the query values of the current URL, for example
+//
+//	type Filters struct {
+//		UserID int       `route:"id"`
+//		Page   int       `query:"page" default:"1"`
+//		Tags   []string  `query:"tag"`
+//		Since  time.Time `query:"since,required" layout:"2006-01-02"`
+//	}
+//
+// Supported field types are strings, bools, numbers, time.Time (RFC 3339 unless a layout
+// is given), time.Duration, types implementing encoding.TextUnmarshaler and slices of those.
+// Slices take all the values of a repeated query parameter.
+// If some values cannot be bound, the returned error is a *BindError.
+func (c *Context) Bind(dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Errorf("Bind: destination must be a pointer to a struct, got %T", dest))
+	}
+
+	var query map[string][]string
+	if c.URL != nil {
+		query = c.URL.Query()
+	}
+
+	var errs []*FieldError
+	bindStruct(v.Elem(), c.Params, query, &errs)
+	if len(errs) > 0 {
+		return &BindError{Errors: errs}
+	}
+
+	return nil
+}
+
+func bindStruct(v reflect.Value, params RouteParams, query map[string][]string, errs *[]*FieldError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fv := v.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			bindStruct(fv, params, query, errs)
+			continue
+		}
+
+		source, param, required := bindTarget(field)
+		if source == "" || !fv.CanSet() {
+			continue
+		}
+
+		var values []string
+		switch source {
+		case routeBindTag:
+			if val, ok := params[param]; ok && val != "" {
+				values = []string{val}
+			}
+		case queryBindTag:
+			values = query[param]
+		}
+
+		if len(values) == 0 {
+			if def, ok := field.Tag.Lookup(defaultBindTag); ok {
+				values = []string{def}
+			}
+		}
+
+		ferr := &FieldError{
+			Field:  field.Name,
+			Source: source,
+			Param:  param,
+		}
+
+		if len(values) == 0 {
+			if required {
+				ferr.Err = errParamRequired
+				*errs = append(*errs, ferr)
+			}
+
+			continue
+		}
+
+		layout := field.Tag.Get(timeLayoutTag)
+		if conv.IsList(fv.Type()) {
+			sl := reflect.MakeSlice(fv.Type(), len(values), len(values))
+			for j, val := range values {
+				if err := conv.SetString(sl.Index(j), val, layout); err != nil {
+					ferr.Value, ferr.Err = val, err
+					break
+				}
+			}
+
+			if ferr.Err == nil {
+				fv.Set(sl)
+			}
+		} else if err := conv.SetString(fv, values[0], layout); err != nil {
+			ferr.Value, ferr.Err = values[0], err
+		}
+
+		if ferr.Err != nil {
+			*errs = append(*errs, ferr)
+		}
+	}
+}
+
+// bindTarget returns where the value of a field comes from, according to its tags
+func bindTarget(field reflect.StructField) (source, param string, required bool) {
+	for _, src := range []string{routeBindTag, queryBindTag} {
+		tag, ok := field.Tag.Lookup(src)
+		if !ok || tag == "-" {
+			continue
+		}
+
+		opts := strings.Split(tag, ",")
+		param = opts[0]
+		if param == "" {
+			param = field.Name
+		}
+
+		for _, opt := range opts[1:] {
+			if opt == "required" {
+				required = true
+			}
+		}
+
+		return src, param, required
+	}
+
+	return "", "", false
+}
+
+// BindValue returns an input event handler that converts the value of the event's target
+// to the parameter type of setter and calls it, values that can't be converted are ignored.
+// fuel uses it for the bind attribute of input, textarea and select elements.
+func BindValue(setter interface{}) interface{} {
+	fn := reflect.ValueOf(setter)
+	if fn.Kind() != reflect.Func || fn.Type().NumIn() != 1 {
+		panic(fmt.Errorf("BindValue: setter must be a function with one parameter, got %T", setter))
+	}
+
+	argType := fn.Type().In(0)
+	return WrapHandler(func(evt dom.InputEvent) {
+		arg := reflect.New(argType).Elem()
+		if err := conv.SetString(arg, evt.Value(), ""); err != nil {
+			return
+		}
+
+		fn.Call([]reflect.Value{arg})
+	})
+}
+
+// BindChecked returns a change event handler that calls setter with the checked
+// state of the event's target, fuel uses it for the bind attribute of checkboxes
+func BindChecked(setter func(bool)) interface{} {
+	return WrapHandler(func(evt dom.InputEvent) {
+		setter(evt.Checked())
+	})
+}
@@ -0,0 +1,90 @@
+package wade_test
+
+import (
+	gourl "net/url"
+	"testing"
+	"time"
+
+	"github.com/gowade/wade"
+	"github.com/gowade/wade/dom/memdom"
+)
+
+func TestBindValue(t *testing.T) {
+	input := memdom.NewElement("input")
+	var calls []int
+	input.SetProp("oninput", wade.BindValue(func(n int) {
+		calls = append(calls, n)
+	}))
+
+	for _, val := range []string{"12", "x", "-3"} {
+		input.SetProp("value", val)
+		input.Trigger("input")
+	}
+
+	if len(calls) != 2 || calls[0] != 12 || calls[1] != -3 {
+		t.Errorf("expected the setter to be called with 12 and -3, got %v", calls)
+	}
+}
+
+func TestBindChecked(t *testing.T) {
+	input := memdom.NewElement("input")
+	input.SetAttr("type", "checkbox")
+	var checked []bool
+	input.SetProp("onchange", wade.BindChecked(func(b bool) {
+		checked = append(checked, b)
+	}))
+
+	input.SetProp("checked", true)
+	input.Trigger("change")
+	input.SetProp("checked", false)
+	input.Trigger("change")
+
+	if len(checked) != 2 || !checked[0] || checked[1] {
+		t.Errorf("expected the setter to be called with true and false, got %v", checked)
+	}
+}
+
+func TestContextBind(t *testing.T) {
+	type filters struct {
+		UserID  int       `route:"id"`
+		Page    int       `query:"page" default:"1"`
+		Tags    []string  `query:"tag"`
+		Since   time.Time `query:"since,required" layout:"2006-01-02"`
+		Archive bool      `query:"archive"`
+		Sort    *string   `query:"sort"`
+		Ignored string
+	}
+
+	url, _ := gourl.Parse("/users/3?tag=a&tag=b&since=2016-04-02&archive=on")
+	c := &wade.Context{Params: wade.RouteParams{"id": "3"}, URL: url}
+
+	var f filters
+	if err := c.Bind(&f); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.UserID != 3 || f.Page != 1 || len(f.Tags) != 2 || f.Tags[1] != "b" ||
+		f.Since.Day() != 2 || !f.Archive || f.Sort != nil {
+		t.Errorf("unexpected bound values %+v", f)
+	}
+
+	url, _ = gourl.Parse("/users/x?page=2&archive=maybe")
+	c = &wade.Context{Params: wade.RouteParams{"id": "x"}, URL: url}
+	err := c.Bind(&f)
+	berr, ok := err.(*wade.BindError)
+	if !ok || len(berr.Errors) != 3 {
+		t.Fatalf("expected 3 field errors, got %v", err)
+	}
+
+	expected := map[string]string{
+		"UserID":  `route parameter "id": invalid value "x": must be an integer`,
+		"Since":   `query parameter "since": required`,
+		"Archive": `query parameter "archive": invalid value "maybe": must be a boolean`,
+	}
+
+	for field, msg := range expected {
+		if ferr := berr.Field(field); ferr == nil || ferr.Error() != msg {
+			t.Errorf("%v: expected error %q, got %v", field, msg, ferr)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"github.com/gowade/vdom"
 	"github.com/gowade/wade"
 	"github.com/gowade/wade/driver"
 	_ "github.com/gowade/wade/driver/jsdrv"
@@ -32,6 +31,6 @@ func main() {
 	}
 
 	ctn := wade.FindContainer("#container")
-	vnode := vdom.RenderComponent(worklog, nil)
+	vnode := wade.RenderComponent(worklog, nil)
 	driver.Render(vnode, nil, ctn)
 }
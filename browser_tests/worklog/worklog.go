@@ -68,6 +68,13 @@ func (this *LogRow) toggleClock() {
 	}
 }
 
+// WillUnmount stops the clock when the row leaves the page
+func (this *LogRow) WillUnmount() {
+	if this.ticker != nil {
+		this.ticker.Stop()
+	}
+}
+
 type Hello struct {
 	Name string
 }
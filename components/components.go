@@ -3,7 +3,6 @@ package components
 import (
 	"path"
 
-	"github.com/gopherjs/gopherjs/js"
 	//"github.com/gowade/vdom"
 	"github.com/gowade/wade"
 	"github.com/gowade/wade/dom"
@@ -13,8 +12,13 @@ type Link struct {
 	Path string
 }
 
-func (lnk *Link) OnClick(evt *js.Object) {
-	evt.Call("preventDefault")
+func (lnk *Link) OnClick(evt dom.MouseEvent) {
+	// let the browser open the link in a new tab or window
+	if evt.Button() != 0 || evt.CtrlKey() || evt.MetaKey() || evt.ShiftKey() {
+		return
+	}
+
+	evt.PreventDefault()
 	wade.App().SetURLPath(lnk.Path)
 }
 
@@ -1,6 +1,7 @@
 package wade
 
 import (
+	"context"
 	"fmt"
 	gourl "net/url"
 
@@ -12,13 +13,23 @@ import (
 type RouteParams map[string]string
 
 // ScanTo uses fmt.Sscan to scan the value of the given named parameter to a pointer.
-func (rp RouteParams) ScanTo(dest interface{}, param string) {
+// Use Context.Bind to bind several parameters at once.
+func (rp RouteParams) ScanTo(dest interface{}, param string) error {
 	v, ok := rp[param]
 	if !ok {
 		panic(fmt.Errorf("ScanTo: No parameter with such name %v.", param))
 	}
 
-	fmt.Sscan(v, dest)
+	if _, err := fmt.Sscan(v, dest); err != nil {
+		return &FieldError{
+			Source: routeBindTag,
+			Param:  param,
+			Value:  v,
+			Err:    err,
+		}
+	}
+
+	return nil
 }
 
 // Get returns the string value of the given named parameter
@@ -26,9 +37,14 @@ func (rp RouteParams) Get(param string) string {
 	return rp[param]
 }
 
-// Context provides access to page data and page operations inside a controller function
+// Context provides access to page data and page operations inside a controller function.
+// The embedded context.Context is cancelled when the next route is rendered,
+// pass it to http.Do to abort the requests of a page the user has left.
 type Context struct {
+	context.Context
+
 	router *DefaultRouter
+	group  *RouteGroup
 	Params RouteParams
 	URL    *gourl.URL
 }
@@ -39,7 +55,7 @@ func (c *Context) GoToRoute(routeName string, params ...interface{}) error {
 		return fmt.Errorf(`there's no route named "%v"`, routeName)
 	}
 
-	app.SetURLPath(c.router.PathFromRoute(route, params))
+	app.SetURLPath(c.router.PathFromRoute(route, params...))
 	return nil
 }
 
@@ -53,16 +69,33 @@ func (c *Context) GoToRemoteURL(destURL string) error {
 	return nil
 }
 
+// Render renders the component to the application's container. For a route of a nested group,
+// the component is rendered inside the outlet of the group's layout.
+// Nothing is rendered if another route has been rendered since the context was created.
 func (c *Context) Render(component vdom.Component) error {
-	var oldVdom *vdom.VElement
+	if err := c.Err(); err != nil {
+		return err
+	}
 
+	component, err := c.withLayouts(component)
+	if err != nil {
+		return err
+	}
+
+	// oldVdom stays an untyped nil for the first render,
+	// the driver hydrates server-rendered content then
+	var oldVdom vdom.VNode
 	if c.router.currentComponent != nil {
-		cdata := vdom.GetComponentData(c.router.currentComponent)
-		oldVdom = cdata.VNode()
+		if vnode := vdom.GetComponentData(c.router.currentComponent).VNode(); vnode != nil {
+			oldVdom = vnode
+		}
 	}
 
-	vtree := component.VDOMRender()
+	prev := beginPass(nil)
+	vtree := RenderComponent(component, nil)
 	driver.Render(vtree, oldVdom, app.Container)
+	curPass = prev
+
 	c.router.currentComponent = component
 	return nil
 }
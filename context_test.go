@@ -0,0 +1,37 @@
+package wade
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gowade/vdom"
+	"github.com/gowade/wade/dom"
+	"github.com/gowade/wade/dom/memdom"
+	"github.com/gowade/wade/driver"
+)
+
+func TestFirstRenderHydrates(t *testing.T) {
+	ctn := memdom.NewElement("div")
+	ctn.SetAttr(driver.RenderedAttr, true)
+	app.Container = ctn
+
+	// like the browser driver, the content is hydrated when there's no old tree
+	var hydrated []bool
+	prevRender := driver.Render
+	driver.Render = func(newVdom, oldVdom vdom.VNode, domNode dom.Node) {
+		_, rendered := domNode.(*memdom.Node).Attr(driver.RenderedAttr)
+		hydrated = append(hydrated, oldVdom == nil && rendered)
+	}
+	defer func() {
+		driver.Render = prevRender
+	}()
+
+	c := &Context{Context: context.Background(), router: NewRouter()}
+	if err := c.Render(&testPage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hydrated) != 1 || !hydrated[0] {
+		t.Errorf("the first render of a server-rendered container should be hydrated")
+	}
+}
@@ -22,6 +22,71 @@ type Event interface {
 	JS() *js.Object
 }
 
+// ModifierKeys tells which modifier keys were pressed when an event happened
+type ModifierKeys interface {
+	AltKey() bool
+	CtrlKey() bool
+	ShiftKey() bool
+	MetaKey() bool
+}
+
+// TargetEvent is an event that gives access to the node it has been dispatched to
+type TargetEvent interface {
+	Event
+	EventType() string
+	Target() Node
+	CurrentTarget() Node
+}
+
+// MouseEvent is the event for click, mousedown, mousemove and the other mouse events
+type MouseEvent interface {
+	TargetEvent
+	ModifierKeys
+
+	// Button is the pressed button, 0 for the main button
+	Button() int
+	ClientX() int
+	ClientY() int
+	PageX() int
+	PageY() int
+}
+
+// KeyboardEvent is the event for keydown, keyup and keypress
+type KeyboardEvent interface {
+	TargetEvent
+	ModifierKeys
+
+	// Key is the value of the key, e.g "a", "Enter" or "ArrowLeft"
+	Key() string
+	// Code is the physical key, e.g "KeyA"
+	Code() string
+	KeyCode() int
+	Repeat() bool
+}
+
+// InputEvent is the event for input and change, Value returns the target's value
+type InputEvent interface {
+	TargetEvent
+	Value() string
+	Checked() bool
+}
+
+// FocusEvent is the event for focus, blur, focusin and focusout
+type FocusEvent interface {
+	TargetEvent
+
+	// RelatedTarget is the node losing or gaining the focus, nil if there's none
+	RelatedTarget() Node
+}
+
+// SubmitEvent is the event for a form submission
+type SubmitEvent interface {
+	TargetEvent
+
+	// Form is the submitted form
+	Form() FormEl
+}
+
 func GetDocument() Document {
 	if document == nil {
 		panic(" document has not been set.")
@@ -74,10 +139,18 @@ type Driver interface {
 type FormEl interface {
 	Node
 	IsValid() bool
+
+	// Fields returns the form's input, textarea and select elements, in document order
+	Fields() []InputEl
 }
 
 type InputEl interface {
 	Node
+	// Name returns the value of the name attribute
+	Name() string
+	// InputType returns the type of the input in lower case, "textarea" for a textarea
+	// and "select-one" or "select-multiple" for a select, like the browser's type property
+	InputType() string
 	Value() string
 	SetValue(string)
 	Checked() bool
@@ -1,11 +1,26 @@
 package jsdom
 
 import (
+	"strings"
+
 	"github.com/gopherjs/gopherjs/js"
+	"github.com/gowade/wade/dom"
 )
 
 type FormEl struct{ Node }
 
+func (e FormEl) Fields() []dom.InputEl {
+	elements := e.Get("elements")
+	var l []dom.InputEl
+	for i := 0; i < elements.Length(); i++ {
+		if input, ok := dom.CreateNode(elements.Index(i)).(dom.InputEl); ok {
+			l = append(l, input)
+		}
+	}
+
+	return l
+}
+
 func (e FormEl) IsValid() bool {
 	if e.Get("checkValidity") != js.Undefined {
 		return e.Call("checkValidity").Bool()
@@ -16,6 +31,14 @@ func (e FormEl) IsValid() bool {
 
 type InputEl struct{ Node }
 
+func (e InputEl) Name() string {
+	return e.Get("name").String()
+}
+
+func (e InputEl) InputType() string {
+	return strings.ToLower(e.Get("type").String())
+}
+
 func (e InputEl) Checked() bool {
 	return e.Get("checked").Bool()
 }
@@ -0,0 +1,157 @@
+package jsdom
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"github.com/gowade/wade/dom"
+)
+
+// The typed event interfaces of the dom package are all implemented by Event,
+// the properties missing from an event of another kind have their zero value.
+
+func (e Event) EventType() string {
+	return e.Get("type").String()
+}
+
+func nodeOrNil(obj *js.Object) dom.Node {
+	if obj == nil || obj == js.Undefined {
+		return nil
+	}
+
+	return dom.CreateNode(obj)
+}
+
+func (e Event) Target() dom.Node {
+	return nodeOrNil(e.Get("target"))
+}
+
+func (e Event) CurrentTarget() dom.Node {
+	return nodeOrNil(e.Get("currentTarget"))
+}
+
+func (e Event) RelatedTarget() dom.Node {
+	return nodeOrNil(e.Get("relatedTarget"))
+}
+
+func (e Event) bool(prop string) bool {
+	v := e.Get(prop)
+	return v != js.Undefined && v.Bool()
+}
+
+func (e Event) int(prop string) int {
+	v := e.Get(prop)
+	if v == js.Undefined || v == nil {
+		return 0
+	}
+
+	return v.Int()
+}
+
+func (e Event) string(prop string) string {
+	v := e.Get(prop)
+	if v == js.Undefined || v == nil {
+		return ""
+	}
+
+	return v.String()
+}
+
+func (e Event) AltKey() bool {
+	return e.bool("altKey")
+}
+
+func (e Event) CtrlKey() bool {
+	return e.bool("ctrlKey")
+}
+
+func (e Event) ShiftKey() bool {
+	return e.bool("shiftKey")
+}
+
+func (e Event) MetaKey() bool {
+	return e.bool("metaKey")
+}
+
+func (e Event) Button() int {
+	return e.int("button")
+}
+
+func (e Event) ClientX() int {
+	return e.int("clientX")
+}
+
+func (e Event) ClientY() int {
+	return e.int("clientY")
+}
+
+func (e Event) PageX() int {
+	return e.int("pageX")
+}
+
+func (e Event) PageY() int {
+	return e.int("pageY")
+}
+
+func (e Event) Key() string {
+	return e.string("key")
+}
+
+func (e Event) Code() string {
+	return e.string("code")
+}
+
+func (e Event) Repeat() bool {
+	return e.bool("repeat")
+}
+
+// KeyCode returns the legacy keyCode, or which for older browsers
+func (e Event) KeyCode() int {
+	if c := e.int("keyCode"); c != 0 {
+		return c
+	}
+
+	return e.int("which")
+}
+
+// Value returns the value of the event's target
+func (e Event) Value() string {
+	target := e.Get("target")
+	if target == nil || target == js.Undefined {
+		return ""
+	}
+
+	v := target.Get("value")
+	if v == js.Undefined || v == nil {
+		return ""
+	}
+
+	return v.String()
+}
+
+// Checked returns the checked state of the event's target
+func (e Event) Checked() bool {
+	target := e.Get("target")
+	return target != nil && target != js.Undefined && target.Get("checked").Bool()
+}
+
+// Form returns the form being submitted, or the form of the event's target
+func (e Event) Form() dom.FormEl {
+	target := e.Get("target")
+	if target == nil || target == js.Undefined {
+		return nil
+	}
+
+	if f := target.Get("form"); f != nil && f != js.Undefined {
+		target = f
+	}
+
+	form, _ := dom.CreateNode(target).(dom.FormEl)
+	return form
+}
+
+var (
+	_ dom.MouseEvent    = Event{}
+	_ dom.KeyboardEvent = Event{}
+	_ dom.InputEvent    = Event{}
+	_ dom.FocusEvent    = Event{}
+	_ dom.SubmitEvent   = Event{}
+)
@@ -37,7 +37,7 @@ type driver struct{}
 func (d driver) CreateNode(native interface{}) dom.Node {
 	node := Node{native.(*js.Object)}
 	switch node.Data() {
-	case "input":
+	case "input", "textarea", "select":
 		return InputEl{node}
 	case "form":
 		return FormEl{node}
@@ -0,0 +1,157 @@
+package memdom
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gowade/wade/dom"
+)
+
+type FormEl struct{ *Node }
+
+// IsValid does a simplified version of the browser's constraint validation,
+// checking the required and pattern attributes of the form's fields
+func (e FormEl) IsValid() bool {
+	valid := true
+	e.walk(func(n *Node) {
+		if input, ok := n.wrap().(InputEl); ok && valid {
+			valid = input.IsValid()
+		}
+	})
+
+	return valid
+}
+
+// InputEl is an input, textarea or select element.
+// Its value and checked state are properties that default to the
+// value and checked attributes, like in a browser.
+type InputEl struct{ *Node }
+
+func (e FormEl) Fields() []dom.InputEl {
+	var l []dom.InputEl
+	e.walk(func(n *Node) {
+		if input, ok := n.wrap().(InputEl); ok {
+			l = append(l, input)
+		}
+	})
+
+	return l
+}
+
+func (e InputEl) Name() string {
+	return e.attrs["name"]
+}
+
+func (e InputEl) InputType() string {
+	switch e.data {
+	case "textarea":
+		return "textarea"
+	case "select":
+		if _, ok := e.attrs["multiple"]; ok {
+			return "select-multiple"
+		}
+
+		return "select-one"
+	}
+
+	if t := strings.ToLower(e.attrs["type"]); t != "" {
+		return t
+	}
+
+	return "text"
+}
+
+func (e InputEl) Checked() bool {
+	if v, ok := e.props["checked"]; ok {
+		checked, _ := v.(bool)
+		return checked
+	}
+
+	_, ok := e.attrs["checked"]
+	return ok
+}
+
+func (e InputEl) SetChecked(checked bool) {
+	e.props["checked"] = checked
+}
+
+func (e InputEl) Value() string {
+	if v, ok := e.props["value"]; ok {
+		if s, ok := v.(string); ok {
+			return s
+		}
+
+		return fmt.Sprint(v)
+	}
+
+	switch e.data {
+	case "textarea":
+		return e.Text()
+	case "select":
+		return e.selectValue()
+	}
+
+	v, ok := e.attrs["value"]
+	if t := e.InputType(); !ok && (t == "checkbox" || t == "radio") {
+		// the browser's default value
+		return "on"
+	}
+
+	return v
+}
+
+// selectValue returns the value of the first selected option, or the first option
+func (e InputEl) selectValue() string {
+	var options []*Node
+	e.walk(func(n *Node) {
+		if n.nodeType == dom.ElementNode && n.data == "option" {
+			options = append(options, n)
+		}
+	})
+
+	if len(options) == 0 {
+		return ""
+	}
+
+	selected := options[0]
+	for _, opt := range options {
+		if _, ok := opt.attrs["selected"]; ok {
+			selected = opt
+			break
+		}
+	}
+
+	if v, ok := selected.attrs["value"]; ok {
+		return v
+	}
+
+	return selected.Text()
+}
+
+func (e InputEl) SetValue(value string) {
+	e.props["value"] = value
+}
+
+// IsValid checks the element's value against its required and pattern attributes
+func (e InputEl) IsValid() bool {
+	if _, ok := e.attrs["disabled"]; ok {
+		return true
+	}
+
+	checkable := e.InputType() == "checkbox" || e.InputType() == "radio"
+	if _, ok := e.attrs["required"]; ok {
+		if checkable && !e.Checked() || !checkable && e.Value() == "" {
+			return false
+		}
+	}
+
+	if pattern, ok := e.attrs["pattern"]; ok && !checkable && e.Value() != "" {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err == nil && !re.MatchString(e.Value()) {
+			return false
+		}
+	}
+
+	return true
+}
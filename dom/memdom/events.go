@@ -0,0 +1,220 @@
+package memdom
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+	"github.com/gowade/wade/dom"
+)
+
+// EventInit holds the properties of the typed events, like the dictionary
+// given to the event constructors of a browser
+type EventInit struct {
+	AltKey, CtrlKey, ShiftKey, MetaKey bool
+
+	// mouse events
+	Button                         int
+	ClientX, ClientY, PageX, PageY int
+
+	// keyboard events
+	Key, Code string
+	KeyCode   int
+	Repeat    bool
+
+	// focus events
+	RelatedTarget *Node
+}
+
+// Event is an in-memory DOM event.
+// It implements all the typed event interfaces of the dom package,
+// the properties that aren't set in Init have their zero value.
+type Event struct {
+	Type    string
+	Bubbles bool
+	Init    EventInit
+
+	target, currentTarget *Node
+	defaultPrevented      bool
+	stopped               bool
+}
+
+// NewEvent creates a bubbling event of the given type
+func NewEvent(evtType string) *Event {
+	return &Event{
+		Type:    evtType,
+		Bubbles: true,
+	}
+}
+
+func (e *Event) PreventDefault() {
+	e.defaultPrevented = true
+}
+
+func (e *Event) StopPropagation() {
+	e.stopped = true
+}
+
+// DefaultPrevented returns whether PreventDefault has been called on the event
+func (e *Event) DefaultPrevented() bool {
+	return e.defaultPrevented
+}
+
+func (e *Event) JS() *js.Object {
+	return nil
+}
+
+func (e *Event) EventType() string {
+	return e.Type
+}
+
+func nodeOrNil(n *Node) dom.Node {
+	if n == nil {
+		return nil
+	}
+
+	return n.wrap()
+}
+
+// Target returns the node the event has been dispatched to, nil before it's dispatched
+func (e *Event) Target() dom.Node {
+	return nodeOrNil(e.target)
+}
+
+// CurrentTarget returns the node whose handlers are being called
+func (e *Event) CurrentTarget() dom.Node {
+	return nodeOrNil(e.currentTarget)
+}
+
+func (e *Event) RelatedTarget() dom.Node {
+	return nodeOrNil(e.Init.RelatedTarget)
+}
+
+func (e *Event) AltKey() bool {
+	return e.Init.AltKey
+}
+
+func (e *Event) CtrlKey() bool {
+	return e.Init.CtrlKey
+}
+
+func (e *Event) ShiftKey() bool {
+	return e.Init.ShiftKey
+}
+
+func (e *Event) MetaKey() bool {
+	return e.Init.MetaKey
+}
+
+func (e *Event) Button() int {
+	return e.Init.Button
+}
+
+func (e *Event) ClientX() int {
+	return e.Init.ClientX
+}
+
+func (e *Event) ClientY() int {
+	return e.Init.ClientY
+}
+
+func (e *Event) PageX() int {
+	return e.Init.PageX
+}
+
+func (e *Event) PageY() int {
+	return e.Init.PageY
+}
+
+func (e *Event) Key() string {
+	return e.Init.Key
+}
+
+func (e *Event) Code() string {
+	return e.Init.Code
+}
+
+func (e *Event) KeyCode() int {
+	return e.Init.KeyCode
+}
+
+func (e *Event) Repeat() bool {
+	return e.Init.Repeat
+}
+
+// Value returns the value of the event's target, "" if it's not an input element
+func (e *Event) Value() string {
+	if input, ok := nodeOrNil(e.target).(InputEl); ok {
+		return input.Value()
+	}
+
+	return ""
+}
+
+// Checked returns the checked state of the event's target
+func (e *Event) Checked() bool {
+	if input, ok := nodeOrNil(e.target).(InputEl); ok {
+		return input.Checked()
+	}
+
+	return false
+}
+
+// Form returns the form being submitted, or the form of the event's target
+func (e *Event) Form() dom.FormEl {
+	for n := e.target; n != nil; n = n.parent {
+		if form, ok := n.wrap().(FormEl); ok {
+			return form
+		}
+	}
+
+	return nil
+}
+
+func newEventHandler(handler dom.EventHandler) interface{} {
+	return handler
+}
+
+// AddEventListener registers a handler for the given event type
+func (z *Node) AddEventListener(evtType string, handler dom.EventHandler) {
+	z.listeners[evtType] = append(z.listeners[evtType], handler)
+}
+
+// RemoveEventListeners removes all handlers registered with AddEventListener for the given event type
+func (z *Node) RemoveEventListeners(evtType string) {
+	delete(z.listeners, evtType)
+}
+
+// Dispatch dispatches the event to the node, then to its ancestors if the event bubbles.
+// It returns false if PreventDefault was called by one of the handlers.
+func (z *Node) Dispatch(evt *Event) bool {
+	evt.target = z
+	for n := z; n != nil; n = n.parent {
+		evt.currentTarget = n
+		n.handle(evt)
+		if evt.stopped || !evt.Bubbles {
+			break
+		}
+	}
+
+	return !evt.defaultPrevented
+}
+
+// Trigger dispatches a new bubbling event of the given type to the node
+func (z *Node) Trigger(evtType string) bool {
+	return z.Dispatch(NewEvent(evtType))
+}
+
+// handle calls the node's handlers for the event, text nodes have no
+// on<event> properties but can have listeners
+func (z *Node) handle(evt *Event) {
+	switch fn := z.props["on"+evt.Type].(type) {
+	case dom.EventHandler:
+		fn(evt)
+	case func(dom.Event):
+		fn(evt)
+	case func():
+		fn()
+	}
+
+	for _, handler := range z.listeners[evt.Type] {
+		handler(evt)
+	}
+}
@@ -0,0 +1,371 @@
+// Package memdom is a pure Go, in-memory implementation of the dom package's
+// interfaces. It makes it possible to run and test component logic
+// outside of a browser.
+package memdom
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+	"github.com/gowade/wade/dom"
+)
+
+type driver struct{}
+
+func (d driver) CreateNode(native interface{}) dom.Node {
+	switch n := native.(type) {
+	case *Node:
+		return n.wrap()
+	case dom.Node:
+		return n
+	}
+
+	panic(fmt.Errorf("memdom: cannot create node from %T", native))
+}
+
+func init() {
+	dom.SetDocument(NewDocument())
+	dom.SetDomDriver(driver{})
+	dom.NewEventHandler = newEventHandler
+}
+
+// Node is an in-memory DOM node, either an element or a text node
+type Node struct {
+	nodeType dom.NodeType
+	data     string
+
+	attrs     map[string]string
+	attrOrder []string
+	props     map[string]interface{}
+	listeners map[string][]dom.EventHandler
+
+	parent   *Node
+	children []*Node
+}
+
+// NewElement creates an element node with the given tag name
+func NewElement(tag string) *Node {
+	return &Node{
+		nodeType:  dom.ElementNode,
+		data:      strings.ToLower(tag),
+		attrs:     map[string]string{},
+		props:     map[string]interface{}{},
+		listeners: map[string][]dom.EventHandler{},
+	}
+}
+
+// NewText creates a text node
+func NewText(text string) *Node {
+	return &Node{
+		nodeType:  dom.TextNode,
+		data:      text,
+		listeners: map[string][]dom.EventHandler{},
+	}
+}
+
+// wrap returns the node as the dom interface that matches its tag
+func (z *Node) wrap() dom.Node {
+	if z.nodeType == dom.ElementNode {
+		switch z.data {
+		case "input", "textarea", "select":
+			return InputEl{z}
+		case "form":
+			return FormEl{z}
+		}
+	}
+
+	return z
+}
+
+func nodeList(nodes []*Node) []dom.Node {
+	l := make([]dom.Node, 0, len(nodes))
+	for _, n := range nodes {
+		l = append(l, n.wrap())
+	}
+
+	return l
+}
+
+func (z *Node) Type() dom.NodeType {
+	return z.nodeType
+}
+
+// Data returns the tag name of an element or the content of a text node
+func (z *Node) Data() string {
+	return z.data
+}
+
+func (z *Node) JS() *js.Object {
+	return nil
+}
+
+func (z *Node) Children() []dom.Node {
+	return nodeList(z.children)
+}
+
+// Parent returns the parent node, nil if the node is detached
+func (z *Node) Parent() *Node {
+	return z.parent
+}
+
+// AppendChild adds c as the last child of the node, removing it from its current parent first
+func (z *Node) AppendChild(c *Node) *Node {
+	if c.parent != nil {
+		c.parent.RemoveChild(c)
+	}
+
+	c.parent = z
+	z.children = append(z.children, c)
+	return c
+}
+
+// RemoveChild removes c from the node's children
+func (z *Node) RemoveChild(c *Node) {
+	for i, child := range z.children {
+		if child == c {
+			z.children = append(z.children[:i], z.children[i+1:]...)
+			c.parent = nil
+			return
+		}
+	}
+}
+
+func (z *Node) Clear() {
+	for _, c := range z.children {
+		c.parent = nil
+	}
+
+	z.children = nil
+}
+
+func (z *Node) Find(query string) []dom.Node {
+	sel, err := parseSelector(query)
+	if err != nil {
+		panic(err)
+	}
+
+	var matches []*Node
+	z.walk(func(n *Node) {
+		if n != z && sel.match(n) {
+			matches = append(matches, n)
+		}
+	})
+
+	return nodeList(matches)
+}
+
+// walk calls fn on the node and all its descendants, in document order
+func (z *Node) walk(fn func(*Node)) {
+	fn(z)
+	for _, c := range z.children {
+		c.walk(fn)
+	}
+}
+
+// Attr returns the value of an attribute
+func (z *Node) Attr(attr string) (value string, ok bool) {
+	value, ok = z.attrs[attr]
+	return
+}
+
+func (z *Node) SetAttr(attr string, value interface{}) {
+	var vstr string
+	switch v := value.(type) {
+	case bool:
+		if !v {
+			z.RemoveAttr(attr)
+			return
+		}
+
+		vstr = attr
+	case string:
+		vstr = v
+	default:
+		vstr = fmt.Sprint(v)
+	}
+
+	if _, ok := z.attrs[attr]; !ok {
+		z.attrOrder = append(z.attrOrder, attr)
+	}
+
+	z.attrs[attr] = vstr
+}
+
+func (z *Node) RemoveAttr(attr string) {
+	if _, ok := z.attrs[attr]; !ok {
+		return
+	}
+
+	delete(z.attrs, attr)
+	for i, a := range z.attrOrder {
+		if a == attr {
+			z.attrOrder = append(z.attrOrder[:i], z.attrOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// Prop returns the value of a property
+func (z *Node) Prop(prop string) interface{} {
+	return z.props[prop]
+}
+
+// SetProp sets a property on the node.
+// Like in a browser, a function set as an on* property (e.g "onclick")
+// is called when an event of that type is dispatched to the node.
+func (z *Node) SetProp(prop string, value interface{}) {
+	z.props[prop] = value
+}
+
+func (z *Node) classes() []string {
+	return strings.Fields(z.attrs["class"])
+}
+
+// HasClass returns whether the element has the given class
+func (z *Node) HasClass(class string) bool {
+	for _, c := range z.classes() {
+		if c == class {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (z *Node) SetClass(class string, val bool) {
+	if val == z.HasClass(class) {
+		return
+	}
+
+	if val {
+		z.SetAttr("class", strings.TrimSpace(z.attrs["class"]+" "+class))
+		return
+	}
+
+	var l []string
+	for _, c := range z.classes() {
+		if c != class {
+			l = append(l, c)
+		}
+	}
+
+	z.SetAttr("class", strings.Join(l, " "))
+}
+
+// Text returns the text content of the node and its descendants
+func (z *Node) Text() string {
+	var buf bytes.Buffer
+	z.walk(func(n *Node) {
+		if n.nodeType == dom.TextNode {
+			buf.WriteString(n.data)
+		}
+	})
+
+	return buf.String()
+}
+
+// SetText replaces the node's children with a single text node
+func (z *Node) SetText(text string) {
+	z.Clear()
+	z.AppendChild(NewText(text))
+}
+
+// HTML returns the HTML markup of the node
+func (z *Node) HTML() string {
+	var buf bytes.Buffer
+	z.writeHTML(&buf)
+	return buf.String()
+}
+
+func (z *Node) writeHTML(buf *bytes.Buffer) {
+	if z.nodeType == dom.TextNode {
+		buf.WriteString(escapeHTML(z.data))
+		return
+	}
+
+	buf.WriteString("<" + z.data)
+	attrs := append([]string{}, z.attrOrder...)
+	sort.Strings(attrs)
+	for _, attr := range attrs {
+		fmt.Fprintf(buf, ` %v="%v"`, attr, escapeHTML(z.attrs[attr]))
+	}
+	buf.WriteString(">")
+	if voidElements[z.data] {
+		return
+	}
+
+	for _, c := range z.children {
+		c.writeHTML(buf)
+	}
+
+	buf.WriteString("</" + z.data + ">")
+}
+
+// voidElements are the elements that have no end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "keygen": true, "link": true,
+	"meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+var htmlEscaper = strings.NewReplacer(`&`, "&amp;", `<`, "&lt;", `>`, "&gt;", `"`, "&#34;")
+
+func escapeHTML(s string) string {
+	return htmlEscaper.Replace(s)
+}
+
+// Document is an in-memory HTML document
+type Document struct {
+	*Node
+	head, body *Node
+}
+
+// NewDocument creates an empty HTML document with a head and a body
+func NewDocument() *Document {
+	root := NewElement("html")
+	return &Document{
+		Node: root,
+		head: root.AppendChild(NewElement("head")),
+		body: root.AppendChild(NewElement("body")),
+	}
+}
+
+// Body returns the document's body element
+func (z *Document) Body() *Node {
+	return z.body
+}
+
+// Head returns the document's head element
+func (z *Document) Head() *Node {
+	return z.head
+}
+
+func (z *Document) titleElement() *Node {
+	for _, c := range z.head.children {
+		if c.data == "title" && c.nodeType == dom.ElementNode {
+			return c
+		}
+	}
+
+	return nil
+}
+
+func (z *Document) Title() string {
+	if t := z.titleElement(); t != nil {
+		return t.Text()
+	}
+
+	return ""
+}
+
+func (z *Document) SetTitle(title string) {
+	t := z.titleElement()
+	if t == nil {
+		t = z.head.AppendChild(NewElement("title"))
+	}
+
+	t.SetText(title)
+}
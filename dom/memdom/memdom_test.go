@@ -0,0 +1,122 @@
+package memdom
+
+import (
+	"testing"
+
+	"github.com/gowade/wade/dom"
+)
+
+func testTree() *Node {
+	root := NewElement("div")
+	root.SetAttr("id", "container")
+
+	ul := root.AppendChild(NewElement("ul"))
+	ul.SetAttr("class", "list main")
+	for _, text := range []string{"A", "B", "C"} {
+		li := ul.AppendChild(NewElement("li"))
+		li.SetAttr("data-name", text)
+		li.AppendChild(NewText(text))
+	}
+
+	form := root.AppendChild(NewElement("form"))
+	input := form.AppendChild(NewElement("input"))
+	input.SetAttr("name", "title")
+	input.SetAttr("required", true)
+
+	return root
+}
+
+func TestFind(t *testing.T) {
+	root := testTree()
+	cases := map[string]int{
+		"li":                      3,
+		"ul > li":                 3,
+		"div li":                  3,
+		"#container > li":         0,
+		".list.main li":           3,
+		"li:first-child":          1,
+		"li:last-child":           1,
+		"li + li":                 2,
+		"li ~ li":                 2,
+		"[data-name]":             3,
+		"[data-name='B']":         1,
+		"[data-name^=A], form":    2,
+		"ul[class~=main] li":      3,
+		"input[required]":         1,
+		"*":                       6,
+		"form input:first-child ": 1,
+	}
+
+	for query, n := range cases {
+		if l := root.Find(query); len(l) != n {
+			t.Errorf("%v: expected %v matches, got %v", query, n, len(l))
+		}
+	}
+
+	if _, ok := root.Find("input")[0].(dom.InputEl); !ok {
+		t.Errorf("input element should be a dom.InputEl")
+	}
+}
+
+func TestClassesAndForm(t *testing.T) {
+	root := testTree()
+	ul := root.Find("ul")[0].(*Node)
+	ul.SetClass("main", false)
+	ul.SetClass("active", true)
+	if class, _ := ul.Attr("class"); class != "list active" {
+		t.Errorf("expected class `list active`, got `%v`", class)
+	}
+
+	form := root.Find("form")[0].(dom.FormEl)
+	if form.IsValid() {
+		t.Errorf("form with an empty required field should not be valid")
+	}
+
+	root.Find("input")[0].(dom.InputEl).SetValue("x")
+	if !form.IsValid() {
+		t.Errorf("form should be valid")
+	}
+}
+
+func TestEvents(t *testing.T) {
+	root := testTree()
+	li := root.Find("li")[1].(*Node)
+
+	var calls []string
+	li.SetProp("onclick", dom.NewEventHandler(func(evt dom.Event) {
+		calls = append(calls, "li")
+	}))
+	root.AddEventListener("click", func(evt dom.Event) {
+		calls = append(calls, "root")
+		evt.PreventDefault()
+	})
+
+	if li.Trigger("click") {
+		t.Errorf("Trigger should return false when the default action is prevented")
+	}
+
+	if len(calls) != 2 || calls[0] != "li" || calls[1] != "root" {
+		t.Errorf("unexpected handler calls %v", calls)
+	}
+}
+
+func TestVoidElementsAndText(t *testing.T) {
+	p := NewElement("p")
+	text := p.AppendChild(NewText("a < b"))
+	p.AppendChild(NewElement("br"))
+	img := p.AppendChild(NewElement("img"))
+	img.SetAttr("src", "x.png")
+
+	if html, expected := p.HTML(), `<p>a &lt; b<br><img src="x.png"></p>`; html != expected {
+		t.Errorf("expected `%v`, got `%v`", expected, html)
+	}
+
+	var called bool
+	text.AddEventListener("click", func(dom.Event) {
+		called = true
+	})
+	text.Trigger("click")
+	if !called {
+		t.Errorf("text nodes should accept event listeners")
+	}
+}
@@ -0,0 +1,376 @@
+package memdom
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gowade/wade/dom"
+)
+
+// A subset of CSS selectors is supported:
+// type, universal, #id, .class and [attribute] selectors (with the =, ~=, |=, ^=, $= and *= operators),
+// the :first-child, :last-child, :checked and :disabled pseudo-classes,
+// descendant, child (>), adjacent sibling (+) and general sibling (~) combinators
+// and comma separated selector groups.
+
+type nodeTest func(*Node) bool
+
+type compoundSel []nodeTest
+
+func (c compoundSel) match(n *Node) bool {
+	for _, test := range c {
+		if !test(n) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// complexSel is a list of compound selectors joined by combinators,
+// combs[i] is the combinator between parts[i] and parts[i+1]
+type complexSel struct {
+	parts []compoundSel
+	combs []byte
+}
+
+func (c complexSel) match(n *Node) bool {
+	return c.matchAt(n, len(c.parts)-1)
+}
+
+func (c complexSel) matchAt(n *Node, i int) bool {
+	if n.nodeType != dom.ElementNode || !c.parts[i].match(n) {
+		return false
+	}
+
+	if i == 0 {
+		return true
+	}
+
+	switch c.combs[i-1] {
+	case '>':
+		return n.parent != nil && c.matchAt(n.parent, i-1)
+	case '+':
+		prev := n.prevElementSibling()
+		return prev != nil && c.matchAt(prev, i-1)
+	case '~':
+		for prev := n.prevElementSibling(); prev != nil; prev = prev.prevElementSibling() {
+			if c.matchAt(prev, i-1) {
+				return true
+			}
+		}
+	default:
+		for p := n.parent; p != nil; p = p.parent {
+			if c.matchAt(p, i-1) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+type selectorGroup []complexSel
+
+func (g selectorGroup) match(n *Node) bool {
+	for _, sel := range g {
+		if sel.match(n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (z *Node) elementSiblings() []*Node {
+	if z.parent == nil {
+		return []*Node{z}
+	}
+
+	var l []*Node
+	for _, c := range z.parent.children {
+		if c.nodeType == dom.ElementNode {
+			l = append(l, c)
+		}
+	}
+
+	return l
+}
+
+func (z *Node) prevElementSibling() *Node {
+	var prev *Node
+	for _, c := range z.elementSiblings() {
+		if c == z {
+			return prev
+		}
+		prev = c
+	}
+
+	return nil
+}
+
+type selectorParser struct {
+	query string
+	pos   int
+}
+
+func parseSelector(query string) (selectorGroup, error) {
+	p := &selectorParser{query: query}
+	var group selectorGroup
+	for {
+		sel, err := p.complex()
+		if err != nil {
+			return nil, err
+		}
+
+		group = append(group, sel)
+		p.skipSpaces()
+		if p.eof() {
+			return group, nil
+		}
+
+		if p.query[p.pos] != ',' {
+			return nil, p.errorf("unexpected character %q", p.query[p.pos])
+		}
+		p.pos++
+	}
+}
+
+func (p *selectorParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("memdom: invalid selector %q at %v: %v",
+		p.query, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *selectorParser) eof() bool {
+	return p.pos >= len(p.query)
+}
+
+func (p *selectorParser) skipSpaces() bool {
+	start := p.pos
+	for !p.eof() && strings.IndexByte(" \t\n\r", p.query[p.pos]) != -1 {
+		p.pos++
+	}
+
+	return p.pos > start
+}
+
+func (p *selectorParser) complex() (complexSel, error) {
+	var sel complexSel
+	p.skipSpaces()
+	for {
+		part, err := p.compound()
+		if err != nil {
+			return sel, err
+		}
+		sel.parts = append(sel.parts, part)
+
+		spaced := p.skipSpaces()
+		if p.eof() || p.query[p.pos] == ',' {
+			return sel, nil
+		}
+
+		comb := byte(' ')
+		if c := p.query[p.pos]; c == '>' || c == '+' || c == '~' {
+			comb = c
+			p.pos++
+			p.skipSpaces()
+		} else if !spaced {
+			return sel, p.errorf("unexpected character %q", c)
+		}
+
+		sel.combs = append(sel.combs, comb)
+	}
+}
+
+func isIdentChar(c byte) bool {
+	return c == '-' || c == '_' ||
+		(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (p *selectorParser) ident() (string, error) {
+	start := p.pos
+	for !p.eof() && isIdentChar(p.query[p.pos]) {
+		p.pos++
+	}
+
+	if p.pos == start {
+		return "", p.errorf("expected identifier")
+	}
+
+	return p.query[start:p.pos], nil
+}
+
+func (p *selectorParser) compound() (compoundSel, error) {
+	var sel compoundSel
+	if !p.eof() && p.query[p.pos] == '*' {
+		p.pos++
+		sel = append(sel, func(n *Node) bool { return true })
+	} else if !p.eof() && isIdentChar(p.query[p.pos]) {
+		tag, _ := p.ident()
+		tag = strings.ToLower(tag)
+		sel = append(sel, func(n *Node) bool { return n.data == tag })
+	}
+
+	for !p.eof() {
+		var test nodeTest
+		var err error
+		switch p.query[p.pos] {
+		case '#':
+			p.pos++
+			var id string
+			id, err = p.ident()
+			test = func(n *Node) bool { return n.attrs["id"] == id }
+		case '.':
+			p.pos++
+			var class string
+			class, err = p.ident()
+			test = func(n *Node) bool { return n.HasClass(class) }
+		case '[':
+			p.pos++
+			test, err = p.attribute()
+		case ':':
+			p.pos++
+			test, err = p.pseudoClass()
+		default:
+			if len(sel) == 0 {
+				return nil, p.errorf("expected selector")
+			}
+
+			return sel, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		sel = append(sel, test)
+	}
+
+	if len(sel) == 0 {
+		return nil, p.errorf("expected selector")
+	}
+
+	return sel, nil
+}
+
+func (p *selectorParser) attribute() (nodeTest, error) {
+	p.skipSpaces()
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+
+	if p.eof() {
+		return nil, p.errorf("unterminated attribute selector")
+	}
+
+	if p.query[p.pos] == ']' {
+		p.pos++
+		return func(n *Node) bool {
+			_, ok := n.attrs[name]
+			return ok
+		}, nil
+	}
+
+	op := ""
+	if strings.IndexByte("~|^$*", p.query[p.pos]) != -1 {
+		op = p.query[p.pos : p.pos+1]
+		p.pos++
+	}
+
+	if p.eof() || p.query[p.pos] != '=' {
+		return nil, p.errorf("expected '='")
+	}
+	p.pos++
+	p.skipSpaces()
+
+	value, err := p.attrValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+
+	if p.eof() || p.query[p.pos] != ']' {
+		return nil, p.errorf("expected ']'")
+	}
+	p.pos++
+
+	return func(n *Node) bool {
+		v, ok := n.attrs[name]
+		if !ok {
+			return false
+		}
+
+		switch op {
+		case "~":
+			for _, f := range strings.Fields(v) {
+				if f == value {
+					return true
+				}
+			}
+			return false
+		case "|":
+			return v == value || strings.HasPrefix(v, value+"-")
+		case "^":
+			return value != "" && strings.HasPrefix(v, value)
+		case "$":
+			return value != "" && strings.HasSuffix(v, value)
+		case "*":
+			return value != "" && strings.Contains(v, value)
+		}
+
+		return v == value
+	}, nil
+}
+
+func (p *selectorParser) attrValue() (string, error) {
+	if p.eof() {
+		return "", p.errorf("expected attribute value")
+	}
+
+	quote := p.query[p.pos]
+	if quote != '"' && quote != '\'' {
+		return p.ident()
+	}
+
+	end := strings.IndexByte(p.query[p.pos+1:], quote)
+	if end == -1 {
+		return "", p.errorf("unterminated string")
+	}
+
+	value := p.query[p.pos+1 : p.pos+1+end]
+	p.pos += end + 2
+	return value, nil
+}
+
+func (p *selectorParser) pseudoClass() (nodeTest, error) {
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+
+	switch name {
+	case "first-child":
+		return func(n *Node) bool {
+			return n.elementSiblings()[0] == n
+		}, nil
+	case "last-child":
+		return func(n *Node) bool {
+			sibs := n.elementSiblings()
+			return sibs[len(sibs)-1] == n
+		}, nil
+	case "checked":
+		return func(n *Node) bool {
+			input, ok := n.wrap().(InputEl)
+			return ok && input.Checked()
+		}, nil
+	case "disabled":
+		return func(n *Node) bool {
+			_, ok := n.attrs["disabled"]
+			return ok
+		}, nil
+	}
+
+	return nil, p.errorf("unsupported pseudo-class :%v", name)
+}
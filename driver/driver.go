@@ -14,10 +14,19 @@ const (
 	ServerEnv
 )
 
+// RenderedAttr marks a container whose content has been rendered on the server,
+// the browser driver takes over its nodes instead of rebuilding them
+const RenderedAttr = "data-wade-rendered"
+
 var (
 	env         EnvironmentType = BrowserEnv
+	devMode                     = true
 	routeDriver RouteDriver
 	Render      func(newVdom, oldVdom vdom.VNode, domNode dom.Node)
+
+	// BeforePatch, if set, is called by the browser driver's Render
+	// once the new tree has been diffed, before the DOM is patched
+	BeforePatch func()
 )
 
 func Init(router Router) {
@@ -56,3 +65,12 @@ func Env() EnvironmentType {
 func SetEnv(envType EnvironmentType) {
 	env = envType
 }
+
+// DevMode tells whether drivers should report development warnings
+func DevMode() bool {
+	return devMode
+}
+
+func SetDevMode(on bool) {
+	devMode = on
+}
@@ -0,0 +1,245 @@
+package jsdrv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+
+	"github.com/gowade/vdom"
+	"github.com/gowade/wade/dom"
+	"github.com/gowade/wade/driver"
+)
+
+// isServerRendered tells whether the container's content has been rendered on the server
+func isServerRendered(domNode dom.Node) bool {
+	obj := domNode.JS()
+	return obj != nil && obj.Call("hasAttribute", driver.RenderedAttr).Bool()
+}
+
+// hydrate takes over the server-rendered content of the container. It returns a virtual tree
+// that mirrors the existing nodes, so that diffing the first tree against it only attaches
+// event handlers and refs, and fixes the parts of the markup that don't match.
+func hydrate(newVdom vdom.VNode, domNode dom.Node) vdom.VNode {
+	domNode.RemoveAttr(driver.RenderedAttr)
+
+	h := &hydrator{}
+	children := h.domChildren(domNode.JS(), []vdom.VNode{newVdom})
+	var old vdom.VNode
+	if len(children) > 0 {
+		old = h.mirror(newVdom, children[0], "")
+	}
+
+	h.removeExtra(domNode.JS(), children, 1, "")
+
+	if driver.DevMode() {
+		for _, msg := range h.mismatches {
+			js.Global.Get("console").Call("warn", "wade: hydration mismatch: "+msg)
+		}
+	}
+
+	return old
+}
+
+type hydrator struct {
+	mismatches []string
+}
+
+func (h *hydrator) mismatch(path string, format string, args ...interface{}) {
+	if path == "" {
+		path = "root"
+	}
+
+	h.mismatches = append(h.mismatches, path+": "+fmt.Sprintf(format, args...))
+}
+
+// domChildren returns the child nodes of a DOM node, with the text nodes
+// split the way the virtual children expect them. The server writes adjacent
+// texts together and empty texts not at all, the browser parses them as one node.
+func (h *hydrator) domChildren(parent *js.Object, vchildren []vdom.VNode) []*js.Object {
+	var nodes []*js.Object
+	cs := parent.Get("childNodes")
+	for i := 0; i < cs.Length(); i++ {
+		c := cs.Index(i)
+		switch c.Get("nodeType").Int() {
+		case 1, 3:
+			nodes = append(nodes, c)
+		}
+	}
+
+	doc := js.Global.Get("document")
+	var ret []*js.Object
+	for i, vc := range vchildren {
+		text, isText := vc.(vdom.VText)
+		if !isText {
+			if len(nodes) > 0 {
+				ret = append(ret, nodes[0])
+				nodes = nodes[1:]
+			}
+
+			continue
+		}
+
+		if len(nodes) == 0 || nodes[0].Get("nodeType").Int() != 3 || text == "" {
+			// the text has no node of its own
+			tn := doc.Call("createTextNode", "")
+			var next *js.Object
+			if len(nodes) > 0 {
+				next = nodes[0]
+			}
+			parent.Call("insertBefore", tn, next)
+			ret = append(ret, tn)
+			continue
+		}
+
+		node := nodes[0]
+		nodes = nodes[1:]
+		_, nextIsText := vnodeAt(vchildren, i+1).(vdom.VText)
+		if nextIsText && len(string(text)) < node.Get("length").Int() &&
+			strings.HasPrefix(node.Get("data").String(), string(text)) {
+			// splitText works with UTF-16 offsets
+			rest := node.Call("splitText", js.Global.Get("String").New(string(text)).Length())
+			nodes = append([]*js.Object{rest}, nodes...)
+		}
+
+		ret = append(ret, node)
+	}
+
+	return append(ret, nodes...)
+}
+
+func vnodeAt(l []vdom.VNode, i int) vdom.VNode {
+	if i < len(l) {
+		return l[i]
+	}
+
+	return nil
+}
+
+// mirror returns a virtual node describing the existing DOM node, using the new virtual node
+// for the parts that can't be read back from the DOM
+func (h *hydrator) mirror(vnode vdom.VNode, node *js.Object, path string) vdom.VNode {
+	switch vn := vnode.(type) {
+	case vdom.VText:
+		if node.Get("nodeType").Int() != 3 || node.Get("nodeValue").String() != string(vn) {
+			h.mismatch(path, "expected text %q", string(vn))
+		}
+
+		return shallowVNode(node)
+
+	case *vdom.VElement:
+		if vn == nil {
+			return nil
+		}
+
+		if vn.RenderComponent != nil {
+			el := h.mirror(renderOnce(vn), node, path)
+			return &vdom.VElement{
+				RenderComponent: func(vdom.Component) *vdom.VElement {
+					ve, _ := el.(*vdom.VElement)
+					return ve
+				},
+			}
+		}
+
+		path = strings.TrimPrefix(path+" > "+vn.Tag, " > ")
+		if node.Get("nodeType").Int() != 1 || strings.ToLower(node.Get("tagName").String()) != vn.Tag {
+			h.mismatch(path, "expected element <%v>", vn.Tag)
+			return shallowVNode(node)
+		}
+
+		props := vdom.Properties{}
+		for k, v := range vn.Props {
+			if isHandler(v) {
+				// handlers are attached by the patch
+				continue
+			}
+
+			if !node.Call("hasAttribute", k).Bool() {
+				if b, ok := v.(bool); ok && !b {
+					props[k] = v
+				} else if v != nil {
+					h.mismatch(path, "missing attribute '%v'", k)
+				}
+
+				continue
+			}
+
+			if b, ok := v.(bool); ok && b {
+				props[k] = v
+				continue
+			}
+
+			attr := node.Call("getAttribute", k).String()
+			if attr != fmt.Sprint(v) {
+				h.mismatch(path, "attribute '%v' is %q, expected %q", k, attr, fmt.Sprint(v))
+			}
+			props[k] = attr
+		}
+
+		dchildren := h.domChildren(node, vn.Children)
+		children := make([]vdom.VNode, 0, len(vn.Children))
+		for i, vc := range vn.Children {
+			if i >= len(dchildren) {
+				h.mismatch(path, "missing children")
+				break
+			}
+
+			children = append(children, h.mirror(vc, dchildren[i], path))
+		}
+
+		h.removeExtra(node, dchildren, len(vn.Children), path)
+
+		return vdom.NewElement(vn.Tag, vn.Key, props, children)
+	}
+
+	return nil
+}
+
+// renderOnce renders a component node and makes the node return that render from then on,
+// so that the diff reuses the instance created for the hydration, whose hooks have run,
+// instead of creating another one
+func renderOnce(vn *vdom.VElement) *vdom.VElement {
+	rendered := vn.RenderComponent(nil)
+	vn.RenderComponent = func(vdom.Component) *vdom.VElement {
+		return rendered
+	}
+
+	return rendered
+}
+
+// removeExtra removes the DOM nodes that have no virtual counterpart
+func (h *hydrator) removeExtra(parent *js.Object, nodes []*js.Object, n int, path string) {
+	if len(nodes) <= n {
+		return
+	}
+
+	h.mismatch(path, "%v unexpected node(s)", len(nodes)-n)
+	for _, node := range nodes[n:] {
+		parent.Call("removeChild", node)
+	}
+}
+
+// shallowVNode returns a virtual node for a DOM node that doesn't match,
+// the patch replaces it
+func shallowVNode(node *js.Object) vdom.VNode {
+	if node.Get("nodeType").Int() == 3 {
+		return vdom.VText(node.Get("nodeValue").String())
+	}
+
+	return vdom.NewElement(strings.ToLower(node.Get("tagName").String()), "", nil, nil)
+}
+
+func isHandler(v interface{}) bool {
+	if v == nil {
+		return false
+	}
+
+	switch reflect.TypeOf(v).Kind() {
+	case reflect.Func, reflect.Chan:
+		return true
+	}
+
+	return false
+}
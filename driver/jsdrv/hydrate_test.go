@@ -0,0 +1,63 @@
+//go:build js
+// +build js
+
+package jsdrv
+
+import (
+	"testing"
+
+	"github.com/gowade/vdom"
+)
+
+type counter struct {
+	mounts int
+}
+
+func (c *counter) VDOMRender() *vdom.VElement {
+	return vdom.NewElement("p", "", nil, nil)
+}
+
+func (c *counter) BeforeMount() {
+	c.mounts++
+}
+
+func TestRenderOnce(t *testing.T) {
+	var instances []*counter
+	vn := &vdom.VElement{
+		// like the code generated by fuel, a new instance is created without an old one
+		RenderComponent: func(old vdom.Component) *vdom.VElement {
+			com, ok := old.(*counter)
+			if !ok {
+				com = &counter{}
+				instances = append(instances, com)
+				com.BeforeMount()
+			}
+
+			return com.VDOMRender()
+		},
+	}
+
+	rendered := renderOnce(vn)
+
+	// the diff renders the node of the new tree again
+	if vn.RenderComponent(nil) != rendered {
+		t.Errorf("the diff should get the render of the hydration")
+	}
+
+	if len(instances) != 1 || instances[0].mounts != 1 {
+		t.Errorf("expected a single instance mounted once, got %v", len(instances))
+	}
+}
+
+func TestIsNilVNode(t *testing.T) {
+	var el *vdom.VElement
+	for _, vnode := range []vdom.VNode{nil, el} {
+		if !isNilVNode(vnode) {
+			t.Errorf("%#v should be nil, a first render would not be hydrated", vnode)
+		}
+	}
+
+	if isNilVNode(vdom.NewElement("p", "", nil, nil)) || isNilVNode(vdom.VText("")) {
+		t.Errorf("rendered nodes should not be nil")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gowade/wade/driver"
 	_ "github.com/gowade/wade/driver/jsdrv/shim"
 	_ "github.com/gowade/wade/utils/http/jshttp"
+	"github.com/gowade/wade/utils/storage"
 )
 
 func init() {
@@ -14,4 +15,5 @@ func init() {
 
 	driver.SetRouteDriver(getRouteDriver())
 	driver.SetEnv(driver.BrowserEnv)
+	storage.SetDriver(getStorage("localStorage"), getStorage("sessionStorage"))
 }
@@ -2,11 +2,29 @@ package jsdrv
 
 import (
 	"github.com/gowade/wade/dom"
+	"github.com/gowade/wade/driver"
 	//"github.com/gowade/wade/dom/jsdom"
 	"github.com/gowade/vdom"
 )
 
+// Render patches the DOM inside domNode with the changes from oldVdom to newVdom.
+// On the first render of a container whose content was rendered on the server,
+// the existing nodes are taken over instead of being rebuilt.
 func Render(newVdom, oldVdom vdom.VNode, domNode dom.Node) {
+	if isNilVNode(oldVdom) && isServerRendered(domNode) {
+		oldVdom = hydrate(newVdom, domNode)
+	}
+
 	diff := vdom.Diff(oldVdom, newVdom)
+	if driver.BeforePatch != nil {
+		driver.BeforePatch()
+	}
+
 	vdom.Patch(domNode.JS(), diff)
 }
+
+// isNilVNode tells whether a virtual node is nil, including a nil *vdom.VElement
+func isNilVNode(vnode vdom.VNode) bool {
+	el, ok := vnode.(*vdom.VElement)
+	return vnode == nil || ok && el == nil
+}
@@ -0,0 +1,95 @@
+package jsdrv
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+
+	"github.com/gowade/wade/utils/storage"
+)
+
+// webStorage is a storage driver for localStorage or sessionStorage
+type webStorage struct {
+	obj *js.Object
+}
+
+// getStorage returns the driver for the named storage, or a memory driver
+// if the browser denies access to it, e.g in private browsing
+func getStorage(name string) (drv storage.Driver) {
+	defer func() {
+		if recover() != nil {
+			drv = storage.NewMemory()
+		}
+	}()
+
+	obj := js.Global.Get(name)
+	if obj == js.Undefined || obj == nil {
+		return storage.NewMemory()
+	}
+
+	return webStorage{obj}
+}
+
+func (s webStorage) Get(key string) (string, bool) {
+	v := s.obj.Call("getItem", key)
+	if v == nil {
+		return "", false
+	}
+
+	return v.String(), true
+}
+
+// Set returns an error if the storage's quota is exceeded
+func (s webStorage) Set(key, value string) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			if jsErr, ok := e.(*js.Error); ok {
+				err = jsErr
+				return
+			}
+
+			panic(e)
+		}
+	}()
+
+	s.obj.Call("setItem", key, value)
+	return nil
+}
+
+func (s webStorage) Delete(key string) {
+	s.obj.Call("removeItem", key)
+}
+
+func (s webStorage) Keys() []string {
+	n := s.obj.Get("length").Int()
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = s.obj.Call("key", i).String()
+	}
+
+	return keys
+}
+
+// OnChange reports the changes made by other tabs of the same site
+func (s webStorage) OnChange(fn func(storage.Change)) {
+	js.Global.Call("addEventListener", "storage", func(ev *js.Object) {
+		if ev.Get("storageArea") != s.obj {
+			return
+		}
+
+		c := storage.Change{}
+		if key := ev.Get("key"); key != nil {
+			c.Key = key.String()
+		}
+
+		if old := ev.Get("oldValue"); old != nil {
+			c.OldValue = old.String()
+		}
+
+		if v := ev.Get("newValue"); v != nil {
+			c.NewValue = v.String()
+		} else {
+			c.Deleted = true
+		}
+
+		go fn(c)
+	})
+}
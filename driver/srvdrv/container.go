@@ -0,0 +1,122 @@
+package srvdrv
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+	"github.com/gowade/vdom"
+	"github.com/gowade/wade/dom"
+	"github.com/gowade/wade/driver"
+)
+
+// Container is a dom.Node that stands in for the application container
+// on the server, it holds the virtual DOM tree of the last render
+// so that it can be written out as HTML.
+type Container struct {
+	tag     string
+	attrs   map[string]string
+	classes map[string]bool
+	vtree   vdom.VNode
+}
+
+// NewContainer creates a container element with the given tag and id
+func NewContainer(tag, id string) *Container {
+	ctn := &Container{
+		tag:     tag,
+		attrs:   map[string]string{},
+		classes: map[string]bool{},
+	}
+
+	if id != "" {
+		ctn.attrs["id"] = id
+	}
+
+	return ctn
+}
+
+// VDOM returns the last rendered virtual DOM tree
+func (z *Container) VDOM() vdom.VNode {
+	return z.vtree
+}
+
+// InnerHTML returns the markup of the last rendered tree
+func (z *Container) InnerHTML() string {
+	return RenderString(z.vtree)
+}
+
+// WriteHTML writes the container element and its rendered content to w,
+// the container is marked so that the browser driver hydrates its content
+func (z *Container) WriteHTML(w io.Writer) error {
+	props := vdom.Properties{}
+	for k, v := range z.attrs {
+		props[k] = v
+	}
+
+	if cl := z.className(); cl != "" {
+		props["class"] = cl
+	}
+
+	if z.vtree != nil {
+		props[driver.RenderedAttr] = true
+	}
+
+	return RenderHTML(w, vdom.NewElement(z.tag, "", props, []vdom.VNode{z.vtree}))
+}
+
+func (z *Container) className() string {
+	var l []string
+	for class, on := range z.classes {
+		if on {
+			l = append(l, class)
+		}
+	}
+	sort.Strings(l)
+
+	return strings.Join(l, " ")
+}
+
+func (z *Container) Type() dom.NodeType {
+	return dom.ElementNode
+}
+
+func (z *Container) Find(query string) []dom.Node {
+	return nil
+}
+
+func (z *Container) Data() string {
+	return z.tag
+}
+
+func (z *Container) Children() []dom.Node {
+	return nil
+}
+
+func (z *Container) SetAttr(attr string, value interface{}) {
+	if v, ok := attrValue(value); ok {
+		z.attrs[attr] = v
+	} else {
+		delete(z.attrs, attr)
+	}
+}
+
+func (z *Container) SetProp(prop string, value interface{}) {
+	z.SetAttr(prop, value)
+}
+
+func (z *Container) RemoveAttr(attr string) {
+	delete(z.attrs, attr)
+}
+
+func (z *Container) Clear() {
+	z.vtree = nil
+}
+
+func (z *Container) JS() *js.Object {
+	return nil
+}
+
+func (z *Container) SetClass(class string, on bool) {
+	z.classes[class] = on
+}
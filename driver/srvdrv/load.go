@@ -0,0 +1,34 @@
+// Package srvdrv is the server-side driver, it renders the virtual DOM tree
+// to HTML markup instead of patching a browser DOM.
+//
+// A typical request handler looks like this:
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		srvdrv.RenderRequest(r, func(rd *srvdrv.RouteDriver) {
+//			rec := wadehttp.NewRecorder(&serverside.ServerBackend{Server: api, ClientReq: r})
+//			wadehttp.SetDriver(rec)
+//			ctn := srvdrv.NewContainer("div", "container")
+//			wade.InitApp("/", newRouter(), ctn)
+//
+//			if url, ok := rd.Redirect(); ok {
+//				http.Redirect(w, r, url.String(), http.StatusFound)
+//				return
+//			}
+//
+//			ctn.WriteHTML(w)
+//			rec.WriteScript(w) // the client answers the same requests from the recorded responses
+//		})
+//	}
+//
+// The drivers and the application are process globals, RenderRequest renders
+// the requests one at a time so that they don't interfere.
+package srvdrv
+
+import (
+	"github.com/gowade/wade/driver"
+)
+
+func init() {
+	driver.Render = Render
+	driver.SetEnv(driver.ServerEnv)
+}
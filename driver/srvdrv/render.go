@@ -0,0 +1,153 @@
+package srvdrv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/gowade/vdom"
+	"github.com/gowade/wade/dom"
+)
+
+var (
+	// elements that must not have a closing tag
+	voidElements = map[string]bool{
+		"area": true, "base": true, "br": true, "col": true, "embed": true,
+		"hr": true, "img": true, "input": true, "keygen": true, "link": true,
+		"meta": true, "param": true, "source": true, "track": true, "wbr": true,
+	}
+
+	// elements whose text content must not be escaped
+	rawTextElements = map[string]bool{
+		"script": true, "style": true,
+	}
+)
+
+// Render is the server-side implementation of driver.Render.
+// If domNode is a *Container the new tree is stored in it, otherwise
+// the rendered markup is set as the node's innerHTML property.
+func Render(newVdom, oldVdom vdom.VNode, domNode dom.Node) {
+	if ctn, ok := domNode.(*Container); ok {
+		ctn.vtree = newVdom
+		return
+	}
+
+	domNode.SetProp("innerHTML", RenderString(newVdom))
+}
+
+// RenderString returns the HTML markup of a virtual DOM tree
+func RenderString(node vdom.VNode) string {
+	var buf bytes.Buffer
+	RenderHTML(&buf, node)
+	return buf.String()
+}
+
+// RenderHTML writes the HTML markup of a virtual DOM tree to w
+func RenderHTML(w io.Writer, node vdom.VNode) error {
+	bw := bufio.NewWriter(w)
+	err := writeNode(bw, node, false)
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+func writeNode(w *bufio.Writer, node vdom.VNode, rawText bool) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+
+	case vdom.VText:
+		if rawText {
+			_, err := w.WriteString(string(n))
+			return err
+		}
+
+		_, err := w.WriteString(html.EscapeString(string(n)))
+		return err
+
+	case *vdom.VElement:
+		if n == nil {
+			return nil
+		}
+
+		if n.RenderComponent != nil {
+			return writeNode(w, n.RenderComponent(nil), rawText)
+		}
+
+		return writeElement(w, n)
+	}
+
+	return fmt.Errorf("unhandled virtual DOM node type %T", node)
+}
+
+func writeElement(w *bufio.Writer, el *vdom.VElement) error {
+	w.WriteByte('<')
+	w.WriteString(el.Tag)
+	writeAttrs(w, el.Props)
+	w.WriteByte('>')
+
+	if voidElements[el.Tag] {
+		return nil
+	}
+
+	for _, c := range el.Children {
+		if err := writeNode(w, c, rawTextElements[el.Tag]); err != nil {
+			return err
+		}
+	}
+
+	w.WriteString("</")
+	w.WriteString(el.Tag)
+	return w.WriteByte('>')
+}
+
+// writeAttrs writes the element's properties as HTML attributes, sorted by name.
+// Event handlers and other function values only make sense in the browser so they are skipped.
+func writeAttrs(w *bufio.Writer, props vdom.Properties) {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v, ok := attrValue(props[k])
+		if !ok {
+			continue
+		}
+
+		w.WriteByte(' ')
+		w.WriteString(k)
+		if v != "" {
+			w.WriteString(`="`)
+			w.WriteString(html.EscapeString(v))
+			w.WriteByte('"')
+		}
+	}
+}
+
+// attrValue returns the string form of an attribute value,
+// ok is false if the attribute should not be rendered
+func attrValue(value interface{}) (v string, ok bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case bool:
+		return "", v
+	case string:
+		return v, true
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Func, reflect.Chan:
+		return "", false
+	}
+
+	return fmt.Sprint(value), true
+}
@@ -0,0 +1,41 @@
+package srvdrv
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gowade/vdom"
+)
+
+func TestRenderString(t *testing.T) {
+	tree := vdom.NewElement("div", "", vdom.Properties{
+		"class":   "row",
+		"hidden":  false,
+		"checked": true,
+		"onclick": func() {},
+	}, []vdom.VNode{
+		vdom.NewElement("h4", "", nil, []vdom.VNode{vdom.VText("A < B")}),
+		nil,
+		vdom.NewElement("input", "", vdom.Properties{"value": `"x"`}, nil),
+		vdom.NewElement("script", "", nil, []vdom.VNode{vdom.VText("a < b")}),
+	})
+
+	expected := `<div checked class="row"><h4>A &lt; B</h4>` +
+		`<input value="&#34;x&#34;"><script>a < b</script></div>`
+	if s := RenderString(tree); s != expected {
+		t.Fatalf("expected `%v`, got `%v`", expected, s)
+	}
+}
+
+func TestContainer(t *testing.T) {
+	ctn := NewContainer("div", "container")
+	ctn.SetClass("app", true)
+	Render(vdom.NewElement("p", "", nil, []vdom.VNode{vdom.VText("hi")}), nil, ctn)
+
+	var buf bytes.Buffer
+	ctn.WriteHTML(&buf)
+	expected := `<div class="app" data-wade-rendered id="container"><p>hi</p></div>`
+	if s := buf.String(); s != expected {
+		t.Fatalf("expected `%v`, got `%v`", expected, s)
+	}
+}
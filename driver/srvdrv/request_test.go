@@ -0,0 +1,50 @@
+package srvdrv_test
+
+import (
+	"net/http/httptest"
+	gourl "net/url"
+	"sync"
+	"testing"
+
+	"github.com/gowade/vdom"
+	"github.com/gowade/wade"
+	"github.com/gowade/wade/driver"
+	"github.com/gowade/wade/driver/srvdrv"
+)
+
+// pathRouter renders the path of the URL into the application's container
+type pathRouter struct{}
+
+func (pathRouter) PathFromRoute(route string, params ...interface{}) string { return route }
+func (pathRouter) RouteByName(name string) (string, bool)                   { return name, true }
+func (pathRouter) Build()                                                   {}
+
+func (pathRouter) Render(url *gourl.URL) {
+	driver.Render(vdom.NewElement("p", "", nil, []vdom.VNode{vdom.VText(url.Path)}),
+		nil, wade.App().Container)
+}
+
+func TestRenderRequestParallel(t *testing.T) {
+	var wg sync.WaitGroup
+	for _, path := range []string{"/a", "/b"} {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				var html string
+				srvdrv.RenderRequest(httptest.NewRequest("GET", path, nil), func(rd *srvdrv.RouteDriver) {
+					ctn := srvdrv.NewContainer("div", "container")
+					wade.InitApp("/", pathRouter{}, ctn)
+					html = ctn.InnerHTML()
+				})
+
+				if expected := "<p>" + path + "</p>"; html != expected {
+					t.Errorf("expected `%v`, got `%v`", expected, html)
+					return
+				}
+			}
+		}(path)
+	}
+
+	wg.Wait()
+}
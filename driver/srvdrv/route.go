@@ -0,0 +1,76 @@
+package srvdrv
+
+import (
+	"net/http"
+	gourl "net/url"
+	"sync"
+
+	"github.com/gowade/wade/driver"
+)
+
+// RouteDriver is a driver.RouteDriver for an incoming HTTP request.
+// URL changes can't be made on the server, they are recorded instead so
+// that the request handler can respond with a redirect.
+type RouteDriver struct {
+	router   driver.Router
+	url      *gourl.URL
+	redirect *gourl.URL
+}
+
+// NewRouteDriver creates a route driver for the given request
+func NewRouteDriver(req *http.Request) *RouteDriver {
+	url := *req.URL
+	if url.Host == "" {
+		url.Host = req.Host
+	}
+
+	if url.Scheme == "" {
+		url.Scheme = "http"
+		if req.TLS != nil {
+			url.Scheme = "https"
+		}
+	}
+
+	return &RouteDriver{
+		url: &url,
+	}
+}
+
+// renderMu serializes the requests rendered with RenderRequest
+var renderMu sync.Mutex
+
+// SetRequest creates a route driver for the given request and sets it as the current route driver.
+// The route driver is a process global, like the http driver and the application,
+// so a server handling concurrent requests should use RenderRequest instead.
+func SetRequest(req *http.Request) *RouteDriver {
+	rd := NewRouteDriver(req)
+	driver.SetRouteDriver(rd)
+	return rd
+}
+
+// RenderRequest sets the route driver for req and calls fn, which sets the other globals
+// (e.g the http driver) and renders the application. The requests are rendered one at a time,
+// fn must be done with the globals when it returns.
+func RenderRequest(req *http.Request, fn func(rd *RouteDriver)) {
+	renderMu.Lock()
+	defer renderMu.Unlock()
+
+	fn(SetRequest(req))
+}
+
+func (rd *RouteDriver) Init(router driver.Router) {
+	rd.router = router
+}
+
+func (rd *RouteDriver) URL() *gourl.URL {
+	return rd.url
+}
+
+func (rd *RouteDriver) SetURL(url *gourl.URL, local bool) {
+	rd.redirect = url
+}
+
+// Redirect returns the URL that was set during rendering, if any
+func (rd *RouteDriver) Redirect() (url *gourl.URL, ok bool) {
+	return rd.redirect, rd.redirect != nil
+}
@@ -0,0 +1,91 @@
+package wade
+
+import (
+	"github.com/gowade/wade/dom"
+)
+
+// typedEvent implements the typed event interfaces for an event that doesn't,
+// giving the zero value to the properties it lacks
+type typedEvent struct {
+	dom.Event
+}
+
+func (e typedEvent) EventType() string {
+	return ""
+}
+
+func (e typedEvent) Target() dom.Node {
+	return nil
+}
+
+func (e typedEvent) CurrentTarget() dom.Node {
+	return nil
+}
+
+func (e typedEvent) RelatedTarget() dom.Node {
+	return nil
+}
+
+func (e typedEvent) Form() dom.FormEl {
+	return nil
+}
+
+func (e typedEvent) AltKey() bool {
+	return false
+}
+
+func (e typedEvent) CtrlKey() bool {
+	return false
+}
+
+func (e typedEvent) ShiftKey() bool {
+	return false
+}
+
+func (e typedEvent) MetaKey() bool {
+	return false
+}
+
+func (e typedEvent) Button() int {
+	return 0
+}
+
+func (e typedEvent) ClientX() int {
+	return 0
+}
+
+func (e typedEvent) ClientY() int {
+	return 0
+}
+
+func (e typedEvent) PageX() int {
+	return 0
+}
+
+func (e typedEvent) PageY() int {
+	return 0
+}
+
+func (e typedEvent) Key() string {
+	return ""
+}
+
+func (e typedEvent) Code() string {
+	return ""
+}
+
+func (e typedEvent) KeyCode() int {
+	return 0
+}
+
+func (e typedEvent) Repeat() bool {
+	return false
+}
+
+func (e typedEvent) Value() string {
+	return ""
+}
+
+func (e typedEvent) Checked() bool {
+	return false
+}
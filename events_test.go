@@ -0,0 +1,86 @@
+package wade_test
+
+import (
+	gourl "net/url"
+	"testing"
+
+	"github.com/gopherjs/gopherjs/js"
+
+	"github.com/gowade/wade"
+	"github.com/gowade/wade/components"
+	"github.com/gowade/wade/dom"
+	"github.com/gowade/wade/dom/memdom"
+	"github.com/gowade/wade/driver"
+)
+
+type urlRecorder struct {
+	url *gourl.URL
+}
+
+func (r *urlRecorder) Init(driver.Router) {}
+
+func (r *urlRecorder) URL() *gourl.URL {
+	return r.url
+}
+
+func (r *urlRecorder) SetURL(url *gourl.URL, local bool) {
+	r.url = url
+}
+
+// plainEvent only implements dom.Event
+type plainEvent struct{}
+
+func (plainEvent) PreventDefault()  {}
+func (plainEvent) StopPropagation() {}
+
+func (plainEvent) JS() *js.Object {
+	return nil
+}
+
+func TestWrapHandlerMemdom(t *testing.T) {
+	a := memdom.NewElement("a")
+	var button int
+	var target dom.Node
+	a.SetProp("onclick", wade.WrapHandler(func(evt dom.MouseEvent) {
+		button = evt.Button()
+		target = evt.Target()
+	}))
+
+	evt := memdom.NewEvent("click")
+	evt.Init.Button = 1
+	a.Dispatch(evt)
+	if button != 1 || target != dom.Node(a) {
+		t.Errorf("the handler should get the event's properties, got button %v and target %v", button, target)
+	}
+
+	handler := wade.WrapHandler(func(evt dom.KeyboardEvent) {
+		if evt.Key() != "" {
+			t.Errorf("expected an empty key for an event without the property")
+		}
+	}).(dom.EventHandler)
+	handler(plainEvent{})
+}
+
+func TestLinkClickMemdom(t *testing.T) {
+	rd := &urlRecorder{}
+	driver.SetRouteDriver(rd)
+
+	lnk := &components.Link{Path: "/about"}
+	a := memdom.NewElement("a")
+	a.SetProp("onclick", wade.WrapHandler(lnk.OnClick))
+
+	if a.Trigger("click") {
+		t.Errorf("the default action of the click should be prevented")
+	}
+
+	if rd.url == nil || rd.url.Path != "/about" {
+		t.Errorf("expected the URL to be set to /about, got %v", rd.url)
+	}
+
+	evt := memdom.NewEvent("click")
+	evt.Init.CtrlKey = true
+	rd.url = nil
+	if !a.Dispatch(evt) || rd.url != nil {
+		t.Errorf("a click with the ctrl key should be left to the browser")
+	}
+}
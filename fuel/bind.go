@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/gowade/whtml"
+)
+
+const (
+	BindAttrName = "bind"
+)
+
+// isBindAttr tells whether an element's attribute is the bind attribute,
+// attribute names are case-insensitive
+func isBindAttr(key string) bool {
+	return strings.ToLower(key) == BindAttrName
+}
+
+// bindSetter returns the generated setter for the state field that a bind expression refers to
+func (z *htmlCompiler) bindSetter(expr string) (string, bool) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "this.") {
+		return "", false
+	}
+
+	path := strings.TrimPrefix(expr, "this.")
+	for _, sf := range z.stateFields {
+		// promoted fields of embedded structs can be written without the path
+		if sf.Path == path || sf.Name == path {
+			return "this.set" + sf.Name, true
+		}
+	}
+
+	return "", false
+}
+
+func (z *htmlCompiler) bindError(el *whtml.Node, format string, args ...interface{}) error {
+	origin := exprOrigin{
+		comName: z.comName,
+		elTag:   el.Data,
+	}
+
+	err := efmt("%v bind attribute: %v", origin, sfmt(format, args...))
+	return z.posError(z.htmlFile.positions.attr(el, BindAttrName), err)
+}
+
+// bindGenerate compiles the bind attribute of a form element into a value or checked
+// property and an event handler calling the setter of the bound state field.
+// attrs are the element's other attributes, the generated ones are added to it.
+func (z *htmlCompiler) bindGenerate(el *whtml.Node, attrs map[string]string) error {
+	var bindAttr *whtml.Attribute
+	var inputType string
+	for i, attr := range el.Attrs {
+		switch {
+		case isBindAttr(attr.Key):
+			bindAttr = &el.Attrs[i]
+		case strings.ToLower(attr.Key) == "type":
+			inputType = strings.ToLower(attr.Val)
+		}
+	}
+
+	if bindAttr == nil {
+		return nil
+	}
+
+	if bindAttr.Type != whtml.MustacheAttribute {
+		return z.bindError(el, "the value must be a {{ }} expression")
+	}
+
+	tag := strings.ToLower(el.Data)
+	switch tag {
+	case "input", "textarea", "select":
+	default:
+		return z.bindError(el, "only input, textarea and select elements can be bound")
+	}
+
+	if inputType == "radio" || inputType == "file" {
+		return z.bindError(el, "%v inputs cannot be bound", inputType)
+	}
+
+	setter, ok := z.bindSetter(bindAttr.Val)
+	if !ok {
+		return z.bindError(el, "{{ %v }} is not a state field of component %v",
+			bindAttr.Val, z.comName)
+	}
+
+	prop, event, handler := "value", "oninput", "wade.BindValue"
+	switch {
+	case inputType == "checkbox":
+		prop, event, handler = "checked", "onchange", "wade.BindChecked"
+	case tag == "select":
+		event = "onchange"
+	}
+
+	for key := range attrs {
+		if strings.ToLower(key) == prop || strings.ToLower(key) == event {
+			return z.bindError(el, "cannot be used with a '%v' attribute", key)
+		}
+	}
+
+	code := z.attrCode(el, *bindAttr)
+	if prop == "value" {
+		code = sfmt("wade.Str(%v)", code)
+	}
+
+	attrs[prop] = code
+	attrs[event] = sfmt("%v(%v)", handler, setter)
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gowade/whtml"
+)
+
+func TestBindGenerate(t *testing.T) {
+	z := testCompiler(nil)
+	z.comName = "Signup"
+	z.stateFields = []stateFieldTD{
+		{Name: "Email", Type: "string", Path: "Email"},
+		{Name: "Terms", Type: "bool", Path: "Form.Terms"},
+	}
+
+	cases := []struct {
+		el    *whtml.Node
+		attrs map[string]string
+		err   string
+	}{
+		{element("input", exprAttr("Bind", "this.Email")), map[string]string{
+			"value": "wade.Str(this.Email)", "oninput": "wade.BindValue(this.setEmail)"}, ""},
+		{element("input", strAttr("type", "checkbox"), exprAttr("bind", "this.Terms")), map[string]string{
+			"checked": "this.Terms", "onchange": "wade.BindChecked(this.setTerms)"}, ""},
+		{element("select", exprAttr("bind", "this.Form.Terms")), map[string]string{
+			"value": "wade.Str(this.Form.Terms)", "onchange": "wade.BindValue(this.setTerms)"}, ""},
+		{element("input"), map[string]string{}, ""},
+		{element("input", strAttr("bind", "Email")), nil, "must be a {{ }} expression"},
+		{element("div", exprAttr("bind", "this.Email")), nil, "only input, textarea and select"},
+		{element("input", strAttr("type", "radio"), exprAttr("bind", "this.Email")), nil, "radio inputs"},
+		{element("input", exprAttr("bind", "this.Name")), nil, "not a state field"},
+		{element("input", exprAttr("bind", "this.Email"), exprAttr("oninput", "this.f")), nil,
+			"cannot be used with a 'oninput' attribute"},
+	}
+
+	for i, c := range cases {
+		attrs := z.toTplAttrs(c.el, c.el.Attrs)
+		err := z.bindGenerate(c.el, attrs)
+		checkErr(t, i, err, c.err)
+		if err != nil || c.err != "" {
+			continue
+		}
+
+		for key := range attrs {
+			if isBindAttr(key) {
+				t.Errorf("%v: the bind attribute should be left out, got %v", i, attrs)
+			}
+		}
+
+		for key, code := range c.attrs {
+			if markerRegex.ReplaceAllString(attrs[key], "") != code {
+				t.Errorf("%v: expected %v to be %v, got %v", i, key, code, attrs[key])
+			}
+		}
+	}
+}
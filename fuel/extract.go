@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gowade/whtml"
+
+	"github.com/gowade/wade/utils/i18n"
+)
+
+// extractFileMessages returns the ids of the translatable messages in a whtml file
+func extractFileMessages(filePath string) ([]*transMessage, error) {
+	src, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes, err := whtml.Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, efmt("%v: %v", filePath, err)
+	}
+
+	positions := indexPositions(string(src), nodes)
+
+	var msgs []*transMessage
+	var walk func(*whtml.Node) error
+	walk = func(n *whtml.Node) error {
+		if n.Type == whtml.ElementNode {
+			msg, err := transMessageOf(n)
+			if err != nil {
+				if pos := positions.attr(n, TransAttrName); pos.IsValid() {
+					return efmt("%v:%v: %v", filePath, pos, err)
+				}
+
+				return efmt("%v: %v", filePath, err)
+			}
+
+			if msg != nil {
+				msgs = append(msgs, msg)
+				return nil
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	for _, n := range nodes {
+		if err := walk(n); err != nil {
+			return nil, err
+		}
+	}
+
+	return msgs, nil
+}
+
+// extractMessages writes the catalog of the translatable messages in the whtml files
+// under dir, the translations already in the catalog file are kept
+func extractMessages(dir, catalogFile, locale string) error {
+	var msgs []*transMessage
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || name == "vendor") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if filepath.Ext(path) != htmlExt {
+			return nil
+		}
+
+		fileMsgs, err := extractFileMessages(path)
+		msgs = append(msgs, fileMsgs...)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	old := &i18n.Catalog{Locale: locale, Messages: map[string]i18n.Message{}}
+	if data, err := ioutil.ReadFile(catalogFile); err == nil {
+		old, err = i18n.ParseCatalog(data)
+		if err != nil {
+			return efmt("%v: %v", catalogFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	catalog, added, err := mergeCatalog(old, msgs, locale)
+	if err != nil {
+		return efmt("%v: %v", catalogFile, err)
+	}
+
+	var removed int
+	for id := range old.Messages {
+		if _, ok := catalog.Messages[id]; !ok {
+			removed++
+		}
+	}
+
+	data, err := json.MarshalIndent(catalog, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(catalogFile, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("%v: %v messages, %v new, %v obsolete removed\n",
+		catalogFile, len(catalog.Messages), added, removed)
+	return nil
+}
+
+// mergeCatalog returns the catalog of the messages for the locale, keeping the translations
+// of the old catalog, and the number of new messages. The locale of the old catalog is used
+// if locale is empty, a different one is an error.
+func mergeCatalog(old *i18n.Catalog, msgs []*transMessage, locale string) (*i18n.Catalog, int, error) {
+	switch {
+	case locale == "":
+		locale = old.Locale
+	case old.Locale != "" && old.Locale != locale:
+		return nil, 0, efmt("the catalog is for locale %v, not %v", old.Locale, locale)
+	}
+
+	// a catalog for the source locale holds the source texts,
+	// the messages of other catalogs are left to be translated
+	// with the number of plural forms of their locale
+	isSource := locale == "" || locale == i18n.SourceLocale
+	catalog := &i18n.Catalog{Locale: locale, Messages: map[string]i18n.Message{}}
+	var added int
+	for _, msg := range msgs {
+		if m, ok := old.Messages[msg.id]; ok {
+			catalog.Messages[msg.id] = m
+			continue
+		}
+
+		if _, ok := catalog.Messages[msg.id]; ok {
+			continue
+		}
+
+		var forms []string
+		switch {
+		case isSource && msg.count != nil:
+			forms = strings.Split(msg.id, "|")
+		case isSource:
+			forms = []string{msg.id}
+		case msg.count != nil:
+			forms = make([]string, i18n.PluralForms(locale))
+		default:
+			forms = []string{""}
+		}
+
+		catalog.Messages[msg.id] = forms
+		added++
+	}
+
+	return catalog, added, nil
+}
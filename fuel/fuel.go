@@ -2,9 +2,13 @@ package main
 
 import (
 	"go/ast"
+	"go/build"
+	"io/ioutil"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -20,31 +24,131 @@ func generatedFileName(name string) string {
 }
 
 var (
-	gSrcPath string
-	c        = 0
+	importDirCache  = map[string]string{}
+	importPathCache = map[string]string{}
+	gModCacheDir    *string
 )
 
-func srcPath() string {
-	if gSrcPath == "" {
-		gopath := os.Getenv("GOPATH")
-		if gopath == "" {
-			fatal("GOPATH environment variable has not been set, please set it to a correct value.")
+func importPath(imp *ast.ImportSpec) string {
+	return imp.Path.Value[1 : len(imp.Path.Value)-1]
+}
+
+// importDir returns the directory of the package imported with impPath from
+// a package in srcDir. The path is resolved the same way the go tool does it,
+// from GOPATH and vendor directories or, in module mode, from the module's
+// requirements (including replace directives) and the module cache.
+// It returns "" for standard library packages and packages that cannot be found.
+func importDir(srcDir, impPath string) string {
+	key := srcDir + "\x00" + impPath
+	if pdir, ok := importDirCache[key]; ok {
+		return pdir
+	}
+
+	var pdir string
+	bpkg, err := build.Import(impPath, srcDir, build.FindOnly)
+	if err == nil && !bpkg.Goroot {
+		pdir = bpkg.Dir
+	}
+
+	importDirCache[key] = pdir
+	return pdir
+}
+
+// modCacheDir returns the module cache directory, "" if it cannot be determined
+func modCacheDir() string {
+	if gModCacheDir == nil {
+		var dir string
+		out, err := exec.Command("go", "env", "GOMODCACHE").Output()
+		if err == nil {
+			dir = strings.TrimSpace(string(out))
 		}
 
-		gSrcPath = filepath.Join(gopath, "src")
+		gModCacheDir = &dir
 	}
 
-	return gSrcPath
+	return *gModCacheDir
 }
 
-func importPath(imp *ast.ImportSpec) string {
-	return imp.Path.Value[1 : len(imp.Path.Value)-1]
+// inModCache returns whether dir is inside the (read-only) module cache
+func inModCache(dir string) bool {
+	mc := modCacheDir()
+	return mc != "" && strings.HasPrefix(dir, mc+string(filepath.Separator))
 }
 
-func importDir(impPath string) string {
-	pdir := filepath.Join(srcPath(), filepath.FromSlash(impPath))
-	if _, err := os.Stat(pdir); err == nil {
-		return pdir
+// pkgImportPath returns the import path of the package in dir, independently of where
+// it is on the disk: from the module cache path, the module's go.mod or GOPATH.
+// The directory's base name is returned if none applies.
+func pkgImportPath(dir string) string {
+	if ipath, ok := importPathCache[dir]; ok {
+		return ipath
+	}
+
+	ipath := findImportPath(dir)
+	importPathCache[dir] = ipath
+	return ipath
+}
+
+func findImportPath(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	if inModCache(dir) {
+		// e.g github.com/!user/lib@v1.2.0/components
+		rel := filepath.ToSlash(strings.TrimPrefix(dir, modCacheDir()+string(filepath.Separator)))
+		return modVersionRegex.ReplaceAllString(unescapeModPath(rel), "")
+	}
+
+	for d := dir; ; {
+		if data, err := ioutil.ReadFile(filepath.Join(d, "go.mod")); err == nil {
+			if mod := modulePath(data); mod != "" {
+				rel, _ := filepath.Rel(d, dir)
+				return path.Join(mod, filepath.ToSlash(rel))
+			}
+		}
+
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+
+	for _, root := range filepath.SplitList(build.Default.GOPATH) {
+		src := filepath.Join(root, "src") + string(filepath.Separator)
+		if strings.HasPrefix(dir, src) {
+			return filepath.ToSlash(strings.TrimPrefix(dir, src))
+		}
+	}
+
+	return filepath.Base(dir)
+}
+
+var modVersionRegex = regexp.MustCompile(`@[^/]+`)
+
+// unescapeModPath reverts the escaping of upper case letters in the module cache, "!u" is "U"
+func unescapeModPath(p string) string {
+	var buf strings.Builder
+	for i := 0; i < len(p); i++ {
+		if p[i] == '!' && i+1 < len(p) {
+			i++
+			buf.WriteString(strings.ToUpper(p[i : i+1]))
+			continue
+		}
+
+		buf.WriteByte(p[i])
+	}
+
+	return buf.String()
+}
+
+// modulePath returns the module path declared in the content of a go.mod file
+func modulePath(gomod []byte) string {
+	for _, line := range strings.Split(string(gomod), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
 	}
 
 	return ""
@@ -121,6 +225,7 @@ func htmlFileVDOMGenerate(pkg *fuelPkg, file *htmlFile) error {
 		checkFatal(err)
 	}
 
+	file.exprs = nil
 	apkg := newAstPkg(pkg.pkg, pkg.imports)
 	defaultImports(apkg.genImports)
 
@@ -163,14 +268,15 @@ func htmlFileVDOMGenerate(pkg *fuelPkg, file *htmlFile) error {
 
 	for _, com := range file.comDefs {
 		// generate render method
+		stateFields := toTemplateStateFields(comSfMap[com.name])
 		compiler := newComponentHTMLCompiler(file, ofile, com, pkg, nil)
+		compiler.stateFields = stateFields
 		err = compiler.componentGenerate()
 		if err != nil {
 			return err
 		}
 
 		// generate other methods
-		stateFields := toTemplateStateFields(comSfMap[com.name])
 		comMethodsTpl.Execute(ofile, comMethodsTD{
 			Receiver:    "*" + com.name,
 			StateFields: stateFields,
@@ -181,14 +287,30 @@ func htmlFileVDOMGenerate(pkg *fuelPkg, file *htmlFile) error {
 	return nil
 }
 
-func fuelBuild(dir string) error {
+// fuelBuild generates the code of the package in dir and its dependencies, the CSS bundle
+// of the components' styles is written to publicDir if it exists
+func fuelBuild(dir, publicDir string) error {
 	pkg, err := getFuelPkg(dir)
 	checkFatal(err)
 
-	return fuelBuildRec(pkg)
+	if err := fuelBuildRec(pkg); err != nil {
+		return err
+	}
+
+	if fi, err := os.Stat(publicDir); err != nil || !fi.IsDir() {
+		return nil
+	}
+
+	return writeStyleBundle(pkg, filepath.Join(publicDir, styleBundleFile))
 }
 
 func fuelBuildRec(pkg *fuelPkg) error {
+	// packages in the module cache can't be modified,
+	// they have to ship with their generated code
+	if inModCache(pkg.dir) {
+		return nil
+	}
+
 	for _, file := range pkg.htmlFiles {
 		err := htmlFileVDOMGenerate(pkg, file)
 		if err != nil {
@@ -214,5 +336,18 @@ func fuelBuildRec(pkg *fuelPkg) error {
 		}
 	}
 
-	return nil
+	// the generated code of the package and its dependencies is complete now,
+	// the markers in the generated files are only needed by the type check
+	if !pkg.HasMarkup() {
+		return nil
+	}
+
+	err := typeCheckPkg(pkg)
+	for _, file := range pkg.htmlFiles {
+		if lerr := addLineDirectives(componentVDOMFilePath(file), file); err == nil {
+			err = lerr
+		}
+	}
+
+	return err
 }
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -14,12 +16,18 @@ import (
 type comDef struct {
 	name   string
 	markup *whtml.Node
+
+	style string // the content of the component's style elements
+	scope string // the attribute that scopes the style to the component's elements
 }
 
 type htmlFile struct {
 	path    string
 	imports map[string]importedPkg
 	comDefs map[string]comDef //component definitions (top-level capitalized HTML elements)
+
+	positions *posIndex    //source positions of the markup's nodes
+	exprs     []exprOrigin //origins of the Go expressions in the generated code
 }
 
 type importedPkg struct {
@@ -30,6 +38,7 @@ type importedPkg struct {
 type parsedPkg struct {
 	*ast.Package
 	fset *token.FileSet
+	dir  string
 }
 
 type pkgMap map[string]*fuelPkg
@@ -70,7 +79,7 @@ func getFuelPkg(dir string) (*fuelPkg, error) {
 	}
 
 	imports := make(pkgMap)
-	pkgDeps(imports, pkg.Package)
+	pkgDeps(imports, pkg)
 
 	htmlFiles, err := pkgHTMLFiles(dir)
 	if err != nil {
@@ -99,15 +108,15 @@ func getFuelPkg(dir string) (*fuelPkg, error) {
 }
 
 // get dependencies imported from inside the package's source code
-func pkgDeps(imports pkgMap, pkg *ast.Package) {
+func pkgDeps(imports pkgMap, pkg *parsedPkg) {
 	for _, file := range pkg.Files {
 		for _, imp := range file.Imports {
 			importPath := importPath(imp)
 			if _, ok := imports[importPath]; ok {
-				return
+				continue
 			}
 
-			pdir := importDir(importPath)
+			pdir := importDir(pkg.dir, importPath)
 			if pdir != "" {
 				fpkg, err := getFuelPkg(pdir)
 				if err != nil {
@@ -116,7 +125,7 @@ func pkgDeps(imports pkgMap, pkg *ast.Package) {
 
 				imports[importPath] = fpkg
 				if fpkg != nil && err == nil {
-					pkgDeps(imports, fpkg.pkg.Package)
+					pkgDeps(imports, fpkg.pkg)
 				}
 			} else {
 				imports[importPath] = nil
@@ -140,6 +149,7 @@ func parsePkg(dir string) (*parsedPkg, error) {
 			return &parsedPkg{
 				Package: pkg,
 				fset:    fset,
+				dir:     dir,
 			}, nil
 		}
 	}
@@ -155,47 +165,52 @@ func pkgHTMLFiles(dir string) ([]*htmlFile, error) {
 
 	var htmlFiles []*htmlFile
 	for _, filePath := range files {
-		imports, comDefs, err := parseHTMLFile(filePath)
+		imports, comDefs, positions, err := parseHTMLFile(filePath)
 		if err != nil {
 			return nil, err
 		}
 
 		htmlFiles = append(htmlFiles, &htmlFile{
-			path:    filePath,
-			imports: imports,
-			comDefs: comDefs,
+			path:      filePath,
+			imports:   imports,
+			comDefs:   comDefs,
+			positions: positions,
 		})
 	}
 
 	return htmlFiles, nil
 }
 
-// parse a component HTML markup file, returning its imports and component definitions (capitalized top-level elements)
+// parse a component HTML markup file, returning its imports, component definitions (capitalized top-level elements)
+// and the source positions of its nodes
 func parseHTMLFile(filePath string) (
 	imports map[string]importedPkg,
 	comDefs map[string]comDef,
+	positions *posIndex,
 	err error) {
 
 	imports = make(map[string]importedPkg)
 	comDefs = make(map[string]comDef)
 
-	file, err := os.Open(filePath)
+	src, err := ioutil.ReadFile(filePath)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	nodes, err := whtml.Parse(file)
+	nodes, err := whtml.Parse(bytes.NewReader(src))
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
+	positions = indexPositions(string(src), nodes)
+
 	for _, node := range nodes {
 		if node.Type == whtml.ElementNode {
 			// import tag
 			if node.Data == importSTag {
-				impName, pkg, err := htmlImportTag(node)
+				impName, pkg, err := htmlImportTag(node, filepath.Dir(filePath))
 				if err != nil {
-					return nil, nil, err
+					return nil, nil, nil, err
 				}
 
 				imports[impName] = pkg
@@ -203,21 +218,33 @@ func parseHTMLFile(filePath string) (
 
 			// component definition element
 			if isCapitalized(node.Data) {
+				style, err := extractStyles(node)
+				if err != nil {
+					return nil, nil, nil, err
+				}
+
 				cleanGarbageTextChildren(node)
 
-				comDefs[node.Data] = comDef{
+				def := comDef{
 					name:   node.Data,
 					markup: node.FirstChild,
+					style:  style,
+				}
+
+				if strings.TrimSpace(style) != "" {
+					def.scope = scopeAttrName(filePath, node.Data)
 				}
+
+				comDefs[node.Data] = def
 			}
 		}
 	}
 
-	return imports, comDefs, nil
+	return imports, comDefs, positions, nil
 }
 
-// process an import tag and the package it imports
-func htmlImportTag(node *whtml.Node) (name string, pkg importedPkg, err error) {
+// process an import tag and the package it imports, dir is the directory of the HTML file
+func htmlImportTag(node *whtml.Node, dir string) (name string, pkg importedPkg, err error) {
 	var path string
 	for _, attr := range node.Attrs {
 		switch attr.Key {
@@ -237,7 +264,7 @@ func htmlImportTag(node *whtml.Node) (name string, pkg importedPkg, err error) {
 	}
 
 	pkg.importPath = path
-	if pdir := importDir(path); pdir != "" {
+	if pdir := importDir(dir, path); pdir != "" {
 		pkg.fuelPkg, err = getFuelPkg(pdir)
 		if err != nil {
 			return
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gowade/whtml"
+)
+
+// test helpers to build markup trees and packages, the whtml parser isn't used
+// so that the tests don't depend on its output
+
+func element(tag string, attrs ...whtml.Attribute) *whtml.Node {
+	return &whtml.Node{Type: whtml.ElementNode, Data: tag, Attrs: attrs}
+}
+
+func textNode(text string) *whtml.Node {
+	return &whtml.Node{Type: whtml.TextNode, Data: text}
+}
+
+func mustacheNode(expr string) *whtml.Node {
+	return &whtml.Node{Type: whtml.MustacheNode, Data: expr}
+}
+
+func appendChildren(parent *whtml.Node, children ...*whtml.Node) *whtml.Node {
+	for _, c := range children {
+		c.Parent = parent
+		if parent.FirstChild == nil {
+			parent.FirstChild = c
+		} else {
+			parent.LastChild.NextSibling = c
+			c.PrevSibling = parent.LastChild
+		}
+		parent.LastChild = c
+	}
+
+	return parent
+}
+
+func strAttr(key, val string) whtml.Attribute {
+	return whtml.Attribute{Key: key, Val: val, Type: whtml.StringAttribute}
+}
+
+// interpAttr is a string attribute with mustaches, val is the format string, e.g "%v px"
+func interpAttr(key, val string, mustaches ...string) whtml.Attribute {
+	return whtml.Attribute{Key: key, Val: val, Type: whtml.StringAttribute, Mustaches: mustaches}
+}
+
+func exprAttr(key, expr string) whtml.Attribute {
+	return whtml.Attribute{Key: key, Val: expr, Type: whtml.MustacheAttribute}
+}
+
+func boolAttr(key string) whtml.Attribute {
+	return whtml.Attribute{Key: key, Type: whtml.BoolAttribute}
+}
+
+// testPkg creates a package in dir from the Go source src ("" for none)
+// and the markup of its components, keyed by name
+func testPkg(t *testing.T, dir, src string, markups map[string]*whtml.Node) *fuelPkg {
+	fset := token.NewFileSet()
+	files := map[string]*ast.File{}
+	if src != "" {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, "app.go"), src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files[filepath.Join(dir, "app.go")] = file
+	}
+
+	hf := &htmlFile{
+		path:    filepath.Join(dir, "app"+htmlExt),
+		comDefs: map[string]comDef{},
+	}
+
+	pkg := &fuelPkg{
+		dir:       dir,
+		pkg:       &parsedPkg{Package: &ast.Package{Name: "app", Files: files}, fset: fset, dir: dir},
+		htmlFiles: []*htmlFile{hf},
+		coms:      comMap{},
+	}
+
+	for name, markup := range markups {
+		hf.comDefs[name] = comDef{name: name, markup: markup}
+		pkg.coms[name] = hf
+	}
+
+	pkg.comStructs = pkgComponents(pkg.pkg.Package, pkg.coms)
+	return pkg
+}
+
+func testCompiler(pkg *fuelPkg) *htmlCompiler {
+	return &htmlCompiler{htmlFile: &htmlFile{}, pkg: pkg}
+}
+
+// checkErr checks that err contains expected, or is nil if expected is empty
+func checkErr(t *testing.T, name interface{}, err error, expected string) {
+	t.Helper()
+	switch {
+	case expected == "" && err != nil:
+		t.Errorf("%v: unexpected error %v", name, err)
+	case expected != "" && (err == nil || !strings.Contains(err.Error(), expected)):
+		t.Errorf("%v: expected an error with %q, got %v", name, expected, err)
+	}
+}
@@ -14,11 +14,17 @@ const (
 )
 
 type refsMap map[string]string
+
+// comSpec describes how a component can be instantiated
 type comSpec struct {
-	childrenField string
+	slots map[string]slotSpec // the slots declared in its markup, "" is the default slot
+	props map[string]propSpec // the fields of its struct, nil if the struct is unknown
+
+	// whether props has all the fields, it doesn't when an embedded type is unknown
+	completeProps bool
 }
 
-type comSpecMap map[string]comSpec
+type comSpecMap map[string]*comSpec
 
 func newHTMLCompiler(htmlFileName string, w io.Writer, root *whtml.Node) *htmlCompiler {
 	return &htmlCompiler{
@@ -41,6 +47,7 @@ func newComponentHTMLCompiler(
 		w:        w,
 		root:     comDef.markup,
 		comName:  comDef.name,
+		scope:    comDef.scope,
 		pkg:      pkg,
 		comSpec:  comSpec,
 	}
@@ -58,6 +65,9 @@ type htmlCompiler struct {
 	pkg      *fuelPkg
 	comSpec  comSpecMap
 	comName  string
+	scope    string // scope attribute added to the elements, if the component has a style
+
+	stateFields []stateFieldTD // state fields of the component, for bind attributes
 }
 
 const (
@@ -77,15 +87,46 @@ func extractKeyFromAttrs(attrs []whtml.Attribute) (key whtml.Attribute, retAttrs
 	return key, attrs
 }
 
-func toTplAttrs(attrs []whtml.Attribute) map[string]string {
+func (z *htmlCompiler) toTplAttrs(el *whtml.Node, attrs []whtml.Attribute) map[string]string {
 	m := make(map[string]string)
 	for _, attr := range attrs {
-		m[attr.Key] = attributeValueCode(attr)
+		if isBindAttr(attr.Key) || isTransAttr(attr.Key) {
+			continue
+		}
+
+		code := z.attrCode(el, attr)
+		if attr.Type == whtml.MustacheAttribute && isEventAttr(attr.Key) {
+			// handlers with a typed event parameter need to be wrapped
+			code = sfmt("wade.WrapHandler(%v)", code)
+		}
+
+		m[attr.Key] = code
 	}
 
 	return m
 }
 
+// nonEventAttrs are the attributes starting with "on" that aren't event handlers
+var nonEventAttrs = map[string]bool{
+	"open": true,
+}
+
+// isEventAttr tells whether an element's attribute is an event handler, "on" followed
+// by a lowercase event name, e.g onclick
+func isEventAttr(key string) bool {
+	if len(key) <= 2 || !strings.HasPrefix(key, "on") || nonEventAttrs[key] {
+		return false
+	}
+
+	for _, c := range key[2:] {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+
+	return true
+}
+
 func refNameFromAttr(attrName string) string {
 	var buf bytes.Buffer
 	for i, c := range attrName {
@@ -149,22 +190,47 @@ func (z *htmlCompiler) elementGenerate(
 
 	key, htmlAttrs := extractKeyFromAttrs(el.Attrs)
 
-	children, err := z.childrenGenerate(el, da, refs)
+	msg, err := transMessageOf(el)
 	if err != nil {
+		return z.transError(el, err)
+	}
+
+	var children []childCode
+	if msg != nil {
+		var buf bytes.Buffer
+		if err := z.transGenerate(&buf, el, msg); err != nil {
+			return err
+		}
+
+		children = []childCode{{Code: &buf}}
+	} else {
+		children, err = z.childrenGenerate(el, da, refs)
+		if err != nil {
+			return err
+		}
+	}
+
+	attrs := z.toTplAttrs(el, htmlAttrs)
+	if err := z.bindGenerate(el, attrs); err != nil {
 		return err
 	}
 
+	if z.scope != "" {
+		attrs[z.scope] = "``"
+	}
+
 	return must(elementVDOMTpl.Execute(w, elementVDOMTD{
 		Tag:      el.Data,
-		Key:      attributeValueCode(key),
-		Attrs:    toTplAttrs(htmlAttrs),
+		Mark:     z.elementMark(el),
+		Key:      z.attrCode(el, key),
+		Attrs:    attrs,
 		Children: children,
 	}))
 }
 
 func (z *htmlCompiler) mustacheNodeGenerate(w io.Writer, node *whtml.Node) error {
 	return must(textNodeVDOMTpl.Execute(w, textNodeVDOMTD{
-		Text: valueToStrCode(node.Data),
+		Text: z.mustacheCode(node),
 	}))
 }
 
@@ -180,16 +246,24 @@ func (z *htmlCompiler) comInstGenerate(
 	da *declArea, refs refsMap,
 	info *comInfo) error {
 
+	if err := z.checkComAttrs(node, info); err != nil {
+		return err
+	}
+
 	fieldsAss := make([]fieldAssTD, 0, len(node.Attrs))
 	for _, attr := range node.Attrs {
 		if isCapitalized(attr.Key) {
 			fieldsAss = append(fieldsAss, fieldAssTD{
 				Name:  attr.Key,
-				Value: attributeValueCode(attr),
+				Value: z.attrCode(node, attr),
 			})
 		}
 	}
 
+	if err := z.checkSlotFills(node, info); err != nil {
+		return err
+	}
+
 	var childrenCode []childCode
 	childrenCode, err := z.childrenGenerate(node, da, nil)
 	if err != nil {
@@ -201,17 +275,26 @@ func (z *htmlCompiler) comInstGenerate(
 		comType = sfmt("%v.%v", info.importSelector, info.name)
 	}
 
+	// the component being generated owns the components created in its markup
+	parent := "nil"
+	if z.comName != "" {
+		parent = "this"
+	}
+
 	return must(comCreateTpl.Execute(w, comCreateTD{
 		ComName:      info.name,
 		ComType:      comType,
 		Decls:        da.code(),
 		ChildrenCode: childrenCode,
 		FieldsAss:    fieldsAss,
+		Mark:         z.elementMark(node),
+		Parent:       parent,
 	}))
 }
 
 type comInfo struct {
 	name, importSelector string
+	pkg                  *fuelPkg
 }
 
 func (z *htmlCompiler) elComponent(node *whtml.Node) (
@@ -243,6 +326,7 @@ func (z *htmlCompiler) elComponent(node *whtml.Node) (
 
 	if info.name != "" && pkg.coms != nil {
 		if _, ok := pkg.coms[info.name]; ok {
+			info.pkg = pkg
 			return &info, nil
 		} else {
 			return nil, efmt("unknown component %v", info.name)
@@ -261,6 +345,10 @@ func (z *htmlCompiler) nodeGenerate(w io.Writer, node *whtml.Node,
 			return fn(w, node, da, refs)
 		}
 
+		if _, ok := slotFillName(node); ok {
+			return z.slotFillGenerate(w, node, da)
+		}
+
 		if z.pkg != nil {
 			comInfo, err := z.elComponent(node)
 			if err != nil {
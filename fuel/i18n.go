@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gowade/whtml"
+)
+
+const (
+	TransAttrName      = "t"
+	TransCountAttrName = "t-count"
+)
+
+var placeholderRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// transMessage is the translatable text of an element with a t attribute
+type transMessage struct {
+	id    string
+	args  []*whtml.Node // the mustaches for the placeholders of the id
+	count *whtml.Attribute
+}
+
+// placeholderName returns the name of the placeholder for a mustache expression,
+// its position if it's not a simple selector
+func placeholderName(expr string, i int) string {
+	expr = strings.TrimPrefix(strings.TrimSpace(expr), "this.")
+	if placeholderRegex.MatchString(expr) {
+		return expr
+	}
+
+	return strconv.Itoa(i)
+}
+
+// transMessageOf returns the message of an element with a t attribute, nil if it has none.
+// Spaces are collapsed in the message's id, {{mustaches}} become {placeholders}.
+func transMessageOf(el *whtml.Node) (*transMessage, error) {
+	var isTrans bool
+	var count *whtml.Attribute
+	for i, attr := range el.Attrs {
+		switch attr.Key {
+		case TransAttrName:
+			isTrans = true
+		case TransCountAttrName:
+			count = &el.Attrs[i]
+		}
+	}
+
+	if !isTrans {
+		if count != nil {
+			return nil, efmt("%v attribute without a %v attribute", TransCountAttrName, TransAttrName)
+		}
+
+		return nil, nil
+	}
+
+	if count != nil && count.Type != whtml.MustacheAttribute {
+		return nil, efmt(`%v must be a mustache value, e.g %v="{{n}}"`, TransCountAttrName, TransCountAttrName)
+	}
+
+	msg := &transMessage{count: count}
+	names := map[string]string{} // expression -> placeholder name
+	taken := map[string]bool{}
+	var buf bytes.Buffer
+	for c := el.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case whtml.TextNode:
+			buf.WriteString(c.Data)
+
+		case whtml.MustacheNode:
+			expr := strings.TrimSpace(c.Data)
+			name, ok := names[expr]
+			if !ok {
+				name = placeholderName(expr, len(msg.args))
+				if taken[name] {
+					name = strconv.Itoa(len(msg.args))
+				}
+
+				names[expr], taken[name] = name, true
+				msg.args = append(msg.args, c)
+			}
+
+			buf.WriteString("{" + name + "}")
+
+		default:
+			return nil, efmt("<%v> with a %v attribute can only contain text and mustaches",
+				el.Data, TransAttrName)
+		}
+	}
+
+	msg.id = strings.Join(strings.Fields(buf.String()), " ")
+	if msg.id == "" {
+		return nil, efmt("<%v> with a %v attribute has no text", el.Data, TransAttrName)
+	}
+
+	return msg, nil
+}
+
+func isTransAttr(key string) bool {
+	return key == TransAttrName || key == TransCountAttrName
+}
+
+func (z *htmlCompiler) transError(el *whtml.Node, err error) error {
+	return z.posError(z.htmlFile.positions.attr(el, TransAttrName), err)
+}
+
+// transGenerate generates the text node that translates the message of an element
+func (z *htmlCompiler) transGenerate(w io.Writer, el *whtml.Node, msg *transMessage) error {
+	args := make([]string, 0, len(msg.args)+1)
+	fn := "wade.T"
+	if msg.count != nil {
+		fn = "wade.TN"
+		args = append(args, sfmt("int(%v)", z.attrCode(el, *msg.count)))
+	}
+
+	for _, node := range msg.args {
+		args = append(args, z.exprCode(el, "", node.Data, node.Data, z.htmlFile.positions.node(node)))
+	}
+
+	code := sfmt("%v(%v)", fn, strconv.Quote(msg.id))
+	if len(args) > 0 {
+		code = sfmt("%v(%v, %v)", fn, strconv.Quote(msg.id), strings.Join(args, ", "))
+	}
+
+	return must(textNodeVDOMTpl.Execute(w, textNodeVDOMTD{
+		Text: code,
+	}))
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gowade/whtml"
+
+	"github.com/gowade/wade/utils/i18n"
+)
+
+func TestTransMessage(t *testing.T) {
+	tAttr := boolAttr(TransAttrName)
+	msg, err := transMessageOf(appendChildren(element("p", tAttr),
+		textNode("\n  Hello "), mustacheNode(" this.User.Name "), textNode(",\n  you owe "),
+		mustacheNode("fmtMoney(this.Debt)"), textNode(" to "), mustacheNode("this.User.Name"), textNode(" "),
+		mustacheNode("Name"), textNode(" "), mustacheNode("fmtMoney(this.Debt)"), textNode("\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "Hello {User.Name}, you owe {1} to {User.Name} {Name} {1}"
+	if msg.id != expected || len(msg.args) != 3 {
+		t.Errorf("expected %q with 3 args, got %q with %v", expected, msg.id, len(msg.args))
+	}
+
+	countAttr := exprAttr(TransCountAttrName, "n")
+	msg, err = transMessageOf(appendChildren(element("p", tAttr, countAttr),
+		mustacheNode("n"), textNode(" item|"), mustacheNode("n"), textNode(" items")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.id != "{n} item|{n} items" || msg.count == nil || len(msg.args) != 1 {
+		t.Errorf("unexpected plural message %+v", msg)
+	}
+
+	if msg, err := transMessageOf(appendChildren(element("p"), textNode("Hello"))); msg != nil || err != nil {
+		t.Errorf("expected no message without a t attribute, got %+v, %v", msg, err)
+	}
+
+	invalid := []*whtml.Node{
+		appendChildren(element("p", tAttr), textNode("Hello "), appendChildren(element("p"), textNode("world"))),
+		appendChildren(element("p", tAttr), textNode(" \n ")),
+		appendChildren(element("p", countAttr), textNode("Hello")),
+	}
+
+	for i, el := range invalid {
+		if _, err := transMessageOf(el); err == nil {
+			t.Errorf("%v: expected an error", i)
+		}
+	}
+}
+
+func TestMergeCatalog(t *testing.T) {
+	count := exprAttr(TransCountAttrName, "n")
+	msgs := []*transMessage{
+		{id: "Hello"},
+		{id: "{n} item|{n} items", count: &count},
+		{id: "Bye"},
+	}
+
+	old := &i18n.Catalog{Locale: "ru", Messages: map[string]i18n.Message{
+		"Hello":    {"Привет"},
+		"Obsolete": {"x"},
+	}}
+
+	catalog, added, err := mergeCatalog(old, msgs, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int{"Hello": 1, "{n} item|{n} items": 3, "Bye": 1}
+	if catalog.Locale != "ru" || added != 2 || len(catalog.Messages) != len(expected) {
+		t.Errorf("unexpected catalog %+v with %v new messages", catalog, added)
+	}
+
+	for id, forms := range expected {
+		if len(catalog.Messages[id]) != forms {
+			t.Errorf("%v: expected %v forms, got %q", id, forms, catalog.Messages[id])
+		}
+	}
+
+	if catalog.Messages["Hello"][0] != "Привет" {
+		t.Errorf("the translation should be kept, got %q", catalog.Messages["Hello"])
+	}
+
+	source, _, err := mergeCatalog(&i18n.Catalog{}, msgs, i18n.SourceLocale)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m := source.Messages["{n} item|{n} items"]; len(m) != 2 || m[1] != "{n} items" {
+		t.Errorf("a source catalog should have the source forms, got %q", m)
+	}
+
+	_, _, err = mergeCatalog(old, msgs, "pl")
+	checkErr(t, "locale mismatch", err, "the catalog is for locale ru, not pl")
+}
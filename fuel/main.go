@@ -1,11 +1,13 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
 
 	"github.com/gowade/whtml"
 )
@@ -14,11 +16,17 @@ const (
 	defaultIndexFile = "public/index.html"
 )
 
-func buildCmd(dir string, target string) {
-	if target != "" {
+func buildCmd(dir string, args []string) {
+	var indexFile string
+
+	fs := flag.NewFlagSet("build", flag.ExitOnError)
+	fs.StringVar(&indexFile, "i", defaultIndexFile, "HTML index file for your application. The CSS bundle of the components' styles is put into its directory")
+	fs.Parse(args)
+
+	if target := fs.Arg(0); target != "" {
 		buildSingleHTML(target)
 	} else {
-		err := fuelBuild(dir)
+		err := fuelBuild(dir, filepath.Dir(indexFile))
 		checkFatal(err)
 	}
 }
@@ -31,7 +39,7 @@ func serveCmd(dir string, args []string) {
 	)
 
 	fs := flag.NewFlagSet("serve", flag.ExitOnError)
-	fs.StringVar(&indexFile, "i", defaultIndexFile, "HTML index file for your application. The compiled app.js file will be put into its directory")
+	fs.StringVar(&indexFile, "i", defaultIndexFile, "HTML index file for your application. The compiled main.js file will be put into its directory")
 	fs.StringVar(&port, "p", "8888", "HTTP port to serve the application")
 	fs.BoolVar(&serveOnly, "serveonly", false, "Only serve and watch, no code generation")
 	fs.Parse(args)
@@ -44,7 +52,50 @@ func serveCmd(dir string, args []string) {
 		fmt.Println("Running serve-only mode, fuel doesn't generate code..")
 	}
 
-	//NewFuel().Serve(dir, indexFile, port, serveOnly)
+	checkFatal(serve(dir, indexFile, port, serveOnly))
+}
+
+func extractCmd(dir string, args []string) {
+	var (
+		output string
+		locale string
+	)
+
+	fs := flag.NewFlagSet("extract", flag.ExitOnError)
+	fs.StringVar(&output, "o", "messages.json", "Catalog file to write, the translations already in it are kept")
+	fs.StringVar(&locale, "l", "", "Locale of the catalog, it must match the locale of an existing catalog. The messages of a catalog for another locale than the source's are left empty")
+	fs.Parse(args)
+
+	checkFatal(extractMessages(dir, output, locale))
+}
+
+func vetCmd(dir string, args []string) {
+	var jsonOutput bool
+
+	fs := flag.NewFlagSet("vet", flag.ExitOnError)
+	fs.BoolVar(&jsonOutput, "json", false, "Print the issues as a JSON array, with the file, line, col, check and message of each")
+	fs.Parse(args)
+
+	issues, err := vetDir(dir)
+	checkFatal(err)
+
+	if jsonOutput {
+		if issues == nil {
+			issues = []vetIssue{}
+		}
+
+		out, err := json.MarshalIndent(issues, "", "  ")
+		checkFatal(err)
+		fmt.Println(string(out))
+	} else {
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+	}
+
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
 }
 
 func cleanCmd(dir string) {
@@ -87,6 +138,9 @@ func buildSingleHTML(filename string) {
 	checkFatal(err)
 
 	runGofmt(outputFileName)
+
+	// the positions of the markup aren't known, the markers are only removed
+	checkFatal(addLineDirectives(outputFileName, &htmlFile{path: filename}))
 }
 
 func runGofmt(file string) {
@@ -108,7 +162,7 @@ func main() {
 	command := flag.Arg(0)
 	switch command {
 	case "build":
-		buildCmd(dir, flag.Arg(1))
+		buildCmd(dir, flag.Args()[1:])
 
 	case "serve":
 		serveCmd(dir, flag.Args()[1:])
@@ -116,7 +170,13 @@ func main() {
 	case "clean":
 		cleanCmd(dir)
 
+	case "extract":
+		extractCmd(dir, flag.Args()[1:])
+
+	case "vet":
+		vetCmd(dir, flag.Args()[1:])
+
 	default:
-		fatal("Please specify a command. Available commands: build, serve, clean")
+		fatal("Please specify a command. Available commands: build, serve, clean, extract, vet")
 	}
 }
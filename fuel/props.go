@@ -0,0 +1,268 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/gowade/whtml"
+)
+
+// propSpec is a field of a component that can be set with an attribute of its instances
+type propSpec struct {
+	typ  ast.Expr
+	apkg *astPkg // package where the field is declared
+}
+
+// comProps returns the exported fields of a component's struct, including the ones promoted
+// from embedded structs, it returns nil if the struct isn't found, complete is false when
+// the type of an embedded field can't be resolved so some fields may be missing
+func comProps(pkg *fuelPkg, comName string) (props map[string]propSpec, complete bool) {
+	cs, ok := pkg.comStructs[comName]
+	if !ok {
+		return nil, false
+	}
+
+	return structProps(newAstPkg(pkg.pkg, pkg.imports), cs.stype, cs.file)
+}
+
+type embeddedStruct struct {
+	stype *ast.StructType
+	file  *ast.File
+	apkg  *astPkg
+}
+
+// structProps walks a struct and its embedded structs level by level, like Go a field
+// hides the ones with the same name at deeper levels, and names declared more than once
+// at the same level are ambiguous so they are left out
+func structProps(apkg *astPkg, stype *ast.StructType, file *ast.File) (map[string]propSpec, bool) {
+	props := make(map[string]propSpec)
+	hidden := make(map[string]bool)
+	complete := true
+	level := []embeddedStruct{{stype, file, apkg}}
+	for depth := 0; len(level) > 0 && depth <= 8; depth++ {
+		count := make(map[string]int)
+		specs := make(map[string]propSpec)
+		var next []embeddedStruct
+		for _, s := range level {
+			for _, f := range s.stype.Fields.List {
+				names := f.Names
+				if len(names) == 0 {
+					names = []*ast.Ident{ast.NewIdent(fieldName(f))}
+					if est, efile, epkg := s.apkg.structType(s.file, f.Type); est != nil {
+						next = append(next, embeddedStruct{est, efile, epkg})
+					} else if unknownEmbedded(f.Type) {
+						complete = false
+					}
+				}
+
+				for _, name := range names {
+					if !hidden[name.Name] {
+						count[name.Name]++
+						specs[name.Name] = propSpec{typ: f.Type, apkg: s.apkg}
+					}
+				}
+			}
+		}
+
+		for name, n := range count {
+			hidden[name] = true
+			if n == 1 && isCapitalized(name) {
+				props[name] = specs[name]
+			}
+		}
+
+		level = next
+	}
+
+	return props, complete && len(level) == 0
+}
+
+// unknownEmbedded tells whether the type of an embedded field that isn't a struct is
+// unresolved, e.g from a package that isn't parsed, it may then promote fields
+func unknownEmbedded(typ ast.Expr) bool {
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+
+	if ident, ok := typ.(*ast.Ident); ok {
+		// structType resolves the identifiers declared in the package
+		return ident.Obj == nil && !predeclaredTypes[ident.Name]
+	}
+
+	return true
+}
+
+var predeclaredTypes = map[string]bool{
+	"bool": true, "string": true, "error": true, "byte": true, "rune": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+// acceptsBasic tells whether a value of the basic type basic (string or bool) can be assigned
+// to the field, it returns true when it can't be decided from the syntax alone
+func (p propSpec) acceptsBasic(basic string) bool {
+	return p.apkg.acceptsBasic(p.typ, basic, 0)
+}
+
+func (p *astPkg) acceptsBasic(typ ast.Expr, basic string, depth int) bool {
+	if depth > 8 {
+		return true
+	}
+
+	switch t := typ.(type) {
+	case *ast.ParenExpr:
+		return p.acceptsBasic(t.X, basic, depth+1)
+
+	case *ast.InterfaceType:
+		return len(t.Methods.List) == 0
+
+	case *ast.Ident:
+		if predeclaredTypes[t.Name] {
+			return t.Name == basic
+		}
+
+		// a named type declared in the same package, e.g type Color string
+		obj := t.Obj
+		if obj == nil {
+			obj, _ = pkgLookup(p.pkg.Package, t.Name)
+		}
+
+		if obj != nil {
+			if spec, ok := obj.Decl.(*ast.TypeSpec); ok && spec.Type != nil {
+				return p.acceptsBasic(spec.Type, basic, depth+1)
+			}
+		}
+
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType, *ast.FuncType,
+		*ast.ChanType, *ast.StructType:
+		return false
+	}
+
+	return true
+}
+
+// propSuggestion returns the field that a misspelled attribute probably refers to, "" if there's none
+func propSuggestion(key string, props map[string]propSpec) string {
+	best, bestDist := "", len(key)/3+2
+	for _, name := range sortedProps(props) {
+		if strings.EqualFold(name, key) {
+			return name
+		}
+
+		if d := editDistance(strings.ToLower(key), strings.ToLower(name)); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+
+	return best
+}
+
+func sortedProps(props map[string]propSpec) []string {
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cur := row[j]
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			row[j] = min3(row[j]+1, row[j-1]+1, prev+cost)
+			prev = cur
+		}
+	}
+
+	return row[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+
+	if c < a {
+		a = c
+	}
+
+	return a
+}
+
+func (z *htmlCompiler) attrError(n *whtml.Node, key string, err error) error {
+	if pos := z.htmlFile.positions.attr(n, key); pos.IsValid() {
+		return z.posError(pos, err)
+	}
+
+	return z.nodeError(n, err)
+}
+
+// checkComAttrs checks that the attributes of a component instance set existing fields
+// of the component, with values of the right type when it's known from the markup
+func (z *htmlCompiler) checkComAttrs(node *whtml.Node, info *comInfo) error {
+	spec, err := z.comSpecOf(info)
+	if err != nil || spec == nil || spec.props == nil {
+		return err
+	}
+
+	for _, attr := range node.Attrs {
+		if attr.Key == RefAttrName || attr.Key == keyAttrName {
+			continue
+		}
+
+		prop, ok := spec.props[attr.Key]
+		if !ok && !spec.completeProps {
+			// it may be promoted from an embedded struct that isn't known, the generated
+			// code is type checked anyway
+			continue
+		}
+
+		if !ok {
+			var hint string
+			if s := propSuggestion(attr.Key, spec.props); s != "" {
+				hint = sfmt(", did you mean %v?", s)
+			} else if len(spec.props) > 0 {
+				hint = sfmt(" (fields: %v)", strings.Join(sortedProps(spec.props), ", "))
+			}
+
+			return z.attrError(node, attr.Key, efmt("component %v has no field %v%v", info.name, attr.Key, hint))
+		}
+
+		var basic string
+		switch {
+		case attr.Type == whtml.BoolAttribute:
+			basic = "bool"
+		case attr.Type == whtml.StringAttribute:
+			basic = "string"
+		default:
+			// the type of expressions is checked with the generated code
+			continue
+		}
+
+		if !prop.acceptsBasic(basic) {
+			return z.attrError(node, attr.Key, efmt("cannot use a %v value for field %v of %v (type %v), "+
+				"use %v={{ value }} instead", basic, attr.Key, info.name, types.ExprString(prop.typ), attr.Key))
+		}
+	}
+
+	return nil
+}
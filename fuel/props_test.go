@@ -0,0 +1,151 @@
+package main
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/gowade/whtml"
+)
+
+func TestCheckComAttrs(t *testing.T) {
+	src := `package app
+
+type Color string
+
+type Base struct {
+	ID      string
+	visible bool
+}
+
+type Card struct {
+	Base
+	Title    string
+	Count    int
+	Color    Color
+	Disabled bool
+	Items    []string
+	Data     interface{}
+	OnClose  func()
+}
+`
+
+	pkg := testPkg(t, "/app", src, map[string]*whtml.Node{"Card": nil})
+	z := testCompiler(pkg)
+	info := &comInfo{name: "Card", pkg: pkg}
+
+	cases := []struct {
+		attr whtml.Attribute
+		err  string
+	}{
+		{strAttr("Title", "Hello"), ""},
+		{strAttr("ID", "card"), ""},
+		{strAttr("Color", "red"), ""},
+		{strAttr("Data", "x"), ""},
+		{strAttr("ref", "card"), ""},
+		{exprAttr("Count", "this.N"), ""},
+		{exprAttr("Items", "this.Items"), ""},
+		{boolAttr("Disabled"), ""},
+		{strAttr("title", "Hello"), "did you mean Title?"},
+		{strAttr("Titel", "Hello"), "did you mean Title?"},
+		{strAttr("Tilte", "Hello"), "did you mean Title?"},
+		{strAttr("visible", "yes"), "has no field visible"},
+		{strAttr("header", "x"), "(fields: Base, Color, Count, Data, Disabled, ID, Items, OnClose, Title)"},
+		{strAttr("Count", "3"), "cannot use a string value for field Count of Card (type int)"},
+		{strAttr("Items", "a"), "use Items={{ value }} instead"},
+		{boolAttr("Title"), "cannot use a bool value"},
+	}
+
+	for i, c := range cases {
+		err := z.checkComAttrs(element("Card", c.attr), info)
+		checkErr(t, i, err, c.err)
+	}
+}
+
+func TestStructProps(t *testing.T) {
+	src := `package app
+
+type Inner struct {
+	Title string
+	Size  int
+	Depth int
+}
+
+type Base struct {
+	Inner
+	ID   string
+	Name string
+}
+
+type Extra struct {
+	ID   int
+	Name string
+	Size float64
+}
+
+type Card struct {
+	Base
+	Extra
+	Title string
+}
+`
+
+	pkg := testPkg(t, "/app", src, map[string]*whtml.Node{"Card": nil})
+	props, complete := comProps(pkg, "Card")
+	if !complete {
+		t.Errorf("expected the fields of Card to be complete")
+	}
+
+	expected := map[string]string{
+		"Base": "Base", "Extra": "Extra", "Inner": "Inner",
+		"Title": "string", "Size": "float64", "Depth": "int",
+	}
+
+	if len(props) != len(expected) {
+		t.Errorf("expected %v fields, got %v", len(expected), sortedProps(props))
+	}
+
+	for name, typ := range expected {
+		spec, ok := props[name]
+		if !ok {
+			t.Errorf("expected field %v", name)
+			continue
+		}
+
+		if ident, ok := spec.typ.(*ast.Ident); !ok || ident.Name != typ {
+			t.Errorf("expected field %v of type %v, got %v", name, typ, spec.typ)
+		}
+	}
+}
+
+func TestUnknownEmbedded(t *testing.T) {
+	src := `package app
+
+type Card struct {
+	ext.Base
+	error
+	Count int
+}
+`
+
+	pkg := testPkg(t, "/app", src, map[string]*whtml.Node{"Card": nil})
+	if _, complete := comProps(pkg, "Card"); complete {
+		t.Errorf("the fields of Card should be incomplete")
+	}
+
+	z := testCompiler(pkg)
+	info := &comInfo{name: "Card", pkg: pkg}
+	cases := []struct {
+		attr whtml.Attribute
+		err  string
+	}{
+		// ID may be promoted from ext.Base
+		{strAttr("ID", "card"), ""},
+		{exprAttr("Count", "this.N"), ""},
+		{strAttr("Count", "3"), "cannot use a string value for field Count of Card (type int)"},
+	}
+
+	for i, c := range cases {
+		err := z.checkComAttrs(element("Card", c.attr), info)
+		checkErr(t, i, err, c.err)
+	}
+}
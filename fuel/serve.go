@@ -0,0 +1,266 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	compiledJSFile = "main.js"
+	reloadPath     = "/_fuel/reload"
+
+	// changes happening within this duration are handled by a single rebuild
+	rebuildDelay = 200 * time.Millisecond
+)
+
+// reloadScript is injected into the served index file,
+// it reloads the page when the server sends a reload event
+var reloadScript = `<script>(function() {
+	var source = new EventSource("` + reloadPath + `");
+	source.onmessage = function() { window.location.reload(); };
+})();</script>`
+
+type devServer struct {
+	dir       string
+	indexFile string
+	serveOnly bool
+
+	watcher  *fsnotify.Watcher
+	reloader *reloader
+}
+
+// serve runs the development server, it serves the directory containing the index file,
+// rebuilds the application when a .whtml or .go file changes and reloads the open pages
+func serve(dir, indexFile, port string, serveOnly bool) error {
+	indexFile, err := filepath.Abs(indexFile)
+	if err != nil {
+		return err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	s := &devServer{
+		dir:       dir,
+		indexFile: indexFile,
+		serveOnly: serveOnly,
+		watcher:   watcher,
+		reloader:  newReloader(),
+	}
+
+	s.rebuild()
+	go s.watch()
+
+	mux := http.NewServeMux()
+	mux.Handle(reloadPath, s.reloader)
+	mux.HandleFunc("/", s.serveFile)
+
+	fmt.Printf("Serving %v at http://localhost:%v\n", filepath.Dir(indexFile), port)
+	return http.ListenAndServe(":"+port, mux)
+}
+
+// rebuild generates the components' code, compiles the application with GopherJS
+// and updates the list of watched directories
+func (s *devServer) rebuild() bool {
+	pkg, err := getFuelPkg(s.dir)
+	if err != nil {
+		printErr(err)
+		return false
+	}
+
+	if pkg == nil {
+		printErr(efmt("%v: no Go package found", s.dir))
+		return false
+	}
+
+	for _, pdir := range watchedDirs(pkg, map[string]bool{}) {
+		if err := s.watcher.Add(pdir); err != nil {
+			printErr(err)
+		}
+	}
+
+	if !s.serveOnly {
+		if err := fuelBuildRec(pkg); err != nil {
+			printErr(err)
+			return false
+		}
+
+		bundle := filepath.Join(filepath.Dir(s.indexFile), styleBundleFile)
+		if err := writeStyleBundle(pkg, bundle); err != nil {
+			printErr(err)
+			return false
+		}
+	}
+
+	fmt.Println("Compiling with gopherjs...")
+	cmd := exec.Command("gopherjs", "build", "-o",
+		filepath.Join(filepath.Dir(s.indexFile), compiledJSFile))
+	cmd.Dir = s.dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		printErr(efmt("gopherjs build failed with %v", err))
+		return false
+	}
+
+	fmt.Println("Build done.")
+	return true
+}
+
+// watchedDirs returns the directories of a package and its dependencies
+func watchedDirs(pkg *fuelPkg, visited map[string]bool) []string {
+	if pkg == nil || visited[pkg.dir] {
+		return nil
+	}
+
+	visited[pkg.dir] = true
+	dirs := []string{pkg.dir}
+	for _, imp := range pkg.imports {
+		dirs = append(dirs, watchedDirs(imp, visited)...)
+	}
+
+	for _, file := range pkg.htmlFiles {
+		for _, imp := range file.imports {
+			dirs = append(dirs, watchedDirs(imp.fuelPkg, visited)...)
+		}
+	}
+
+	return dirs
+}
+
+func isWatchedFile(name string) bool {
+	base := filepath.Base(name)
+	if strings.HasPrefix(base, genPrefix) || isFuelFile(base) {
+		return false
+	}
+
+	return strings.HasSuffix(base, htmlExt) || strings.HasSuffix(base, ".go")
+}
+
+func (s *devServer) watch() {
+	var timer <-chan time.Time
+	for {
+		select {
+		case evt := <-s.watcher.Events:
+			if isWatchedFile(evt.Name) && evt.Op&fsnotify.Chmod != evt.Op {
+				timer = time.After(rebuildDelay)
+			}
+
+		case err := <-s.watcher.Errors:
+			printErr(err)
+
+		case <-timer:
+			timer = nil
+			fmt.Println("Changes detected, rebuilding...")
+			if s.rebuild() {
+				s.reloader.reload()
+			}
+		}
+	}
+}
+
+// serveFile serves the files in the index file's directory, the index file
+// is served for every path that doesn't match a file, so that the application's
+// router can handle it
+func (s *devServer) serveFile(w http.ResponseWriter, r *http.Request) {
+	publicDir := filepath.Dir(s.indexFile)
+	filePath := filepath.Join(publicDir, filepath.FromSlash(path.Clean("/"+r.URL.Path)))
+	if fi, err := os.Stat(filePath); err == nil && !fi.IsDir() && filePath != s.indexFile {
+		w.Header().Set("Cache-Control", "no-cache")
+		http.ServeFile(w, r, filePath)
+		return
+	}
+
+	content, err := ioutil.ReadFile(s.indexFile)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Write(injectReloadScript(content))
+}
+
+func injectReloadScript(content []byte) []byte {
+	i := bytes.LastIndex(bytes.ToLower(content), []byte("</body>"))
+	if i == -1 {
+		return append(content, reloadScript...)
+	}
+
+	ret := make([]byte, 0, len(content)+len(reloadScript))
+	ret = append(ret, content[:i]...)
+	ret = append(ret, reloadScript...)
+	return append(ret, content[i:]...)
+}
+
+// reloader sends reload events to the open pages using server-sent events
+type reloader struct {
+	mutex   sync.Mutex
+	clients map[chan struct{}]bool
+}
+
+func newReloader() *reloader {
+	return &reloader{
+		clients: map[chan struct{}]bool{},
+	}
+}
+
+func (rl *reloader) reload() {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	for c := range rl.clients {
+		select {
+		case c <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (rl *reloader) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	c := make(chan struct{}, 1)
+	rl.mutex.Lock()
+	rl.clients[c] = true
+	rl.mutex.Unlock()
+
+	defer func() {
+		rl.mutex.Lock()
+		delete(rl.clients, c)
+		rl.mutex.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+
+	for {
+		select {
+		case <-c:
+			fmt.Fprint(w, "data: reload\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
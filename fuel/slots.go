@@ -0,0 +1,242 @@
+package main
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gowade/whtml"
+)
+
+// slotFillPrefix starts the tags that fill the named slots of a component, e.g <slot:header>
+const slotFillPrefix = "slot:"
+
+type (
+	slotSpec struct {
+		required bool
+	}
+
+	slotFillTD struct {
+		Name     string
+		Children []childCode
+	}
+)
+
+var (
+	slotFillCode = `wade.SlotFill([[.Name]], [[template "children" .Children]])`
+
+	slotFillTpl = newTpl("slotFill", slotFillCode)
+)
+
+// slotFillName returns the name of the slot that an element fills
+func slotFillName(node *whtml.Node) (string, bool) {
+	if node.Type != whtml.ElementNode || !strings.HasPrefix(node.Data, slotFillPrefix) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(node.Data, slotFillPrefix), true
+}
+
+// slotTagAttrs returns the name of a slot tag and whether the slot is required
+func slotTagAttrs(n *whtml.Node) (name string, required bool, err error) {
+	for _, attr := range n.Attrs {
+		switch attr.Key {
+		case "name":
+			if attr.Type == whtml.MustacheAttribute || len(attr.Mustaches) > 0 {
+				return "", false, fmtSTagError(slotSTag, "the name must be a constant string")
+			}
+
+			name = attr.Val
+		case "required":
+			required = true
+		default:
+			return "", false, invalidAttribute(slotSTag, attr.Key)
+		}
+	}
+
+	return name, required, nil
+}
+
+func (z *htmlCompiler) nodeError(n *whtml.Node, err error) error {
+	return z.posError(z.htmlFile.positions.node(n), err)
+}
+
+// posError prefixes an error with the markup file and the position it's about,
+// file:line:col like the type errors
+func (z *htmlCompiler) posError(pos srcPos, err error) error {
+	path := z.htmlFile.path
+	switch {
+	case path != "" && pos.IsValid():
+		return efmt("%v:%v: %v", path, pos, err)
+	case path != "":
+		return efmt("%v: %v", path, err)
+	case pos.IsValid():
+		return efmt("%v: %v", pos, err)
+	}
+
+	return err
+}
+
+// slotTagGenerate generates the code for a slot tag, it renders the content that the
+// component's instance gives for the slot, or its children if there's none
+func (z *htmlCompiler) slotTagGenerate(
+	w io.Writer, n *whtml.Node,
+	da *declArea, refs refsMap,
+) error {
+
+	if z.comName == "" {
+		return fmtSTagError(slotSTag, "slots can only be declared inside a component")
+	}
+
+	name, _, err := slotTagAttrs(n)
+	if err != nil {
+		return err
+	}
+
+	approxName := "Default"
+	if name != "" {
+		approxName = exprApproxName(name)
+	}
+
+	varName, cbuf := da.declare(sfmt("slot%v", approxName))
+	w.Write([]byte(varName))
+
+	// the children are the fallback content
+	newDA := newDeclArea(da)
+	children, err := z.childrenGenerate(n, newDA, refs)
+	if err != nil {
+		return err
+	}
+
+	return renderTagVDOMTpl.Execute(cbuf, renderTagVDOMTD{
+		VarName:  varName,
+		Content:  sfmt("this.VDOMSlot(%v)", strconv.Quote(name)),
+		Children: children,
+		Decls:    newDA.code(),
+	})
+}
+
+// slotFillGenerate generates the content of a named slot passed to a component instance
+func (z *htmlCompiler) slotFillGenerate(w io.Writer, n *whtml.Node, da *declArea) error {
+	name, _ := slotFillName(n)
+	if n.Parent != nil {
+		// a single file is compiled without a package, it has no components
+		var info *comInfo
+		if z.pkg != nil {
+			var err error
+			info, err = z.elComponent(n.Parent)
+			if err != nil {
+				return err
+			}
+		}
+
+		if info == nil {
+			return z.nodeError(n, efmt("<%v> must be a direct child of a component instance", n.Data))
+		}
+	}
+
+	children, err := z.childrenGenerate(n, da, nil)
+	if err != nil {
+		return err
+	}
+
+	return must(slotFillTpl.Execute(w, slotFillTD{
+		Name:     strconv.Quote(name),
+		Children: children,
+	}))
+}
+
+// comSpecOf returns the spec of a component, from the slot tags in its markup and its struct
+func (z *htmlCompiler) comSpecOf(info *comInfo) (*comSpec, error) {
+	key := info.name
+	if info.pkg != nil {
+		key = info.pkg.dir + "." + info.name
+	}
+
+	if spec, ok := z.comSpec[key]; ok {
+		return spec, nil
+	}
+
+	if info.pkg == nil || info.pkg.coms[info.name] == nil {
+		return nil, nil
+	}
+
+	spec := &comSpec{slots: map[string]slotSpec{}}
+	spec.props, spec.completeProps = comProps(info.pkg, info.name)
+
+	var walk func(*whtml.Node) error
+	walk = func(n *whtml.Node) error {
+		if n.Type == whtml.ElementNode && n.Data == slotSTag {
+			name, required, err := slotTagAttrs(n)
+			if err != nil {
+				return efmt("%v: %v", info.name, err)
+			}
+
+			spec.slots[name] = slotSpec{required: spec.slots[name].required || required}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if markup := info.pkg.coms[info.name].comDefs[info.name].markup; markup != nil {
+		if err := walk(markup); err != nil {
+			return nil, err
+		}
+	}
+
+	if z.comSpec == nil {
+		z.comSpec = make(comSpecMap)
+	}
+
+	z.comSpec[key] = spec
+	return spec, nil
+}
+
+// checkSlotFills checks that a component instance fills existing slots,
+// and all the required ones
+func (z *htmlCompiler) checkSlotFills(node *whtml.Node, info *comInfo) error {
+	spec, err := z.comSpecOf(info)
+	if err != nil || spec == nil {
+		return err
+	}
+
+	filled := map[string]bool{}
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		name, ok := slotFillName(c)
+		if !ok {
+			if c.Type != whtml.TextNode || strings.TrimSpace(c.Data) != "" {
+				filled[""] = true
+			}
+
+			continue
+		}
+
+		if _, ok := spec.slots[name]; !ok || name == "" {
+			return z.nodeError(c, efmt("component %v has no slot named %q", info.name, name))
+		}
+
+		if filled[name] {
+			return z.nodeError(c, efmt("slot %q of %v is filled twice", name, info.name))
+		}
+
+		filled[name] = true
+	}
+
+	for name, slot := range spec.slots {
+		if slot.required && !filled[name] {
+			if name == "" {
+				return z.nodeError(node, efmt("component %v requires content for its default slot", info.name))
+			}
+
+			return z.nodeError(node, efmt("missing required slot %q of component %v", name, info.name))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gowade/whtml"
+)
+
+func TestCheckSlotFills(t *testing.T) {
+	markup := appendChildren(element("div"),
+		appendChildren(element("header"), element("slot", strAttr("name", "title"), boolAttr("required"))),
+		element("slot"),
+		element("slot", strAttr("name", "footer")))
+
+	pkg := testPkg(t, "/app", "", map[string]*whtml.Node{"Card": markup})
+	z := testCompiler(pkg)
+	info := &comInfo{name: "Card", pkg: pkg}
+
+	cases := []struct {
+		children []*whtml.Node
+		err      string
+	}{
+		{[]*whtml.Node{element("slot:title"), element("p")}, ""},
+		{[]*whtml.Node{element("slot:title"), element("slot:footer")}, ""},
+		{[]*whtml.Node{element("p")}, `missing required slot "title"`},
+		{[]*whtml.Node{element("slot:title"), element("slot:body")}, `no slot named "body"`},
+		{[]*whtml.Node{element("slot:title"), element("slot:title")}, "filled twice"},
+	}
+
+	for i, c := range cases {
+		err := z.checkSlotFills(appendChildren(element("Card"), c.children...), info)
+		checkErr(t, i, err, c.err)
+	}
+}
+
+func TestSlotFillWithoutPkg(t *testing.T) {
+	z := testCompiler(nil)
+	fill := element("slot:title")
+	appendChildren(element("div"), fill)
+
+	var buf bytes.Buffer
+	err := z.slotFillGenerate(&buf, fill, nil)
+	checkErr(t, "single file", err, "must be a direct child of a component instance")
+}
@@ -15,6 +15,8 @@ const (
 	switchSTag  = "switch"
 	caseSTag    = "case"
 	defaultSTag = "default"
+	renderSTag  = "render"
+	slotSTag    = "slot"
 )
 
 type specialTagFunc func(io.Writer, *whtml.Node, *declArea, refsMap) error
@@ -27,6 +29,10 @@ func (z *htmlCompiler) specialTag(tagName string) specialTagFunc {
 		return z.ifTagGenerate
 	case switchSTag:
 		return z.switchTagGenerate
+	case renderSTag:
+		return z.renderTagGenerate
+	case slotSTag:
+		return z.slotTagGenerate
 	}
 
 	return nil
@@ -64,6 +70,13 @@ type (
 		Cases   []*caseTagVDOMTD
 		Default *caseTagVDOMTD
 	}
+
+	renderTagVDOMTD struct {
+		Content  string
+		VarName  string
+		Decls    *bytes.Buffer
+		Children []childCode
+	}
 )
 
 var (
@@ -105,6 +118,14 @@ var (
 	[[end]]
 	}
 	`
+
+	renderTagVDOMCode = `
+	[[.VarName]] := wade.NewVNodeList([[.Content]])
+	if len([[.VarName]]) == 0 {
+		[[.Decls]]
+		[[.VarName]] = [[template "children" .Children]]
+	}
+	`
 )
 
 var (
@@ -112,6 +133,7 @@ var (
 	forTagVDOMTpl    = newTpl("forTag", forTagVDOMCode)
 	ifTagVDOMTpl     = newTpl("ifTag", ifTagVDOMCode)
 	switchTagVDOMTpl = newTpl("switchTag", switchTagVDOMCode)
+	renderTagVDOMTpl = newTpl("renderTag", renderTagVDOMCode)
 )
 
 // exprApproxName tries to return a meaningful name for a control structure variable
@@ -194,7 +216,7 @@ func (z *htmlCompiler) forTagGenerate(
 		KeyName:  keyName,
 		ValName:  valName,
 		VarName:  varName,
-		Items:    attributeValueCode(rangeAttr),
+		Items:    z.attrCode(n, rangeAttr),
 		Children: children,
 		Decls:    newDA.code(),
 	})
@@ -231,7 +253,7 @@ func (z *htmlCompiler) ifTagGenerate(
 
 	return ifTagVDOMTpl.Execute(cbuf, ifTagVDOMTD{
 		VarName:  varName,
-		Cond:     attributeValueCode(condAttr),
+		Cond:     z.attrCode(n, condAttr),
 		Children: children,
 		Decls:    newDA.code(),
 	})
@@ -274,7 +296,7 @@ func (z *htmlCompiler) caseTagGenerate(n *whtml.Node, da *declArea, refs refsMap
 		return nil, err
 	}
 
-	return z.newCaseTagTD(n, da, refs, attributeValueCode(exprAttr))
+	return z.newCaseTagTD(n, da, refs, z.attrCode(n, exprAttr))
 }
 
 func (z *htmlCompiler) switchGetCases(n *whtml.Node, da *declArea, refs refsMap) (
@@ -331,7 +353,7 @@ func (z *htmlCompiler) switchTagGenerate(
 
 	var exprCode string
 	if exprAttr.Val != "" {
-		exprCode = attributeValueCode(exprAttr)
+		exprCode = z.attrCode(n, exprAttr)
 	}
 
 	varName := sfmt("switch%v", exprApproxName(exprAttr.Val))
@@ -350,3 +372,43 @@ func (z *htmlCompiler) switchTagGenerate(
 		Default: deflt,
 	})
 }
+
+// renderTagGenerate generates the code for a render tag, it renders the nodes
+// given by its content attribute, or its children if there are none
+func (z *htmlCompiler) renderTagGenerate(
+	w io.Writer, n *whtml.Node,
+	da *declArea, refs refsMap,
+) error {
+
+	contentAttr := whtml.Attribute{Key: "content"}
+	for _, attr := range n.Attrs {
+		switch attr.Key {
+		case "content":
+			contentAttr = attr
+		default:
+			return invalidAttribute(renderSTag, attr.Key)
+		}
+	}
+
+	if err := attrRequireNotEmpty(renderSTag, contentAttr); err != nil {
+		return err
+	}
+
+	varName := sfmt("render%v", exprApproxName(contentAttr.Val))
+	varName, cbuf := da.declare(varName)
+	w.Write([]byte(varName))
+
+	// the children are the fallback content
+	newDA := newDeclArea(da)
+	children, err := z.childrenGenerate(n, newDA, refs)
+	if err != nil {
+		return err
+	}
+
+	return renderTagVDOMTpl.Execute(cbuf, renderTagVDOMTD{
+		VarName:  varName,
+		Content:  z.attrCode(n, contentAttr),
+		Children: children,
+		Decls:    newDA.code(),
+	})
+}
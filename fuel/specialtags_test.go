@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRenderTagFallback(t *testing.T) {
+	z := testCompiler(nil)
+	tag := appendChildren(element(renderSTag, exprAttr("content", "this.OutletContent()")),
+		appendChildren(element("p"), textNode("Nothing here")))
+
+	var buf bytes.Buffer
+	da := newDeclArea(nil)
+	if err := z.renderTagGenerate(&buf, tag, da, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.String() != "renderOUTLETCONTENT1" {
+		t.Errorf("expected the variable of the content, got %v", buf.String())
+	}
+
+	code := markerRegex.ReplaceAllString(da.code().String(), "")
+	content := strings.Index(code, "wade.NewVNodeList(this.OutletContent())")
+	fallback := strings.Index(code, "if len(renderOUTLETCONTENT1) == 0 {")
+	text := strings.Index(code, `"Nothing here"`)
+	if content == -1 || fallback < content || text < fallback {
+		t.Errorf("expected the children to be rendered when the content is empty, got\n%v", code)
+	}
+
+	err := z.renderTagGenerate(&buf, element(renderSTag), da, nil)
+	checkErr(t, "no content", err, "attribute 'content' cannot be empty")
+}
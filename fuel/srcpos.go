@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gowade/whtml"
+)
+
+// srcPos is a position in a markup file, line and col start at 1
+type srcPos struct {
+	line, col int
+}
+
+func (p srcPos) IsValid() bool {
+	return p.line > 0
+}
+
+func (p srcPos) String() string {
+	return sfmt("%v:%v", p.line, p.col)
+}
+
+// posIndex holds the source positions of the elements, attributes and
+// mustache nodes of a parsed markup file
+type posIndex struct {
+	nodes map[*whtml.Node]srcPos
+	attrs map[*whtml.Node]map[string]srcPos
+}
+
+func (idx *posIndex) node(n *whtml.Node) srcPos {
+	if idx == nil {
+		return srcPos{}
+	}
+
+	return idx.nodes[n]
+}
+
+func (idx *posIndex) attr(n *whtml.Node, key string) srcPos {
+	if idx == nil {
+		return srcPos{}
+	}
+
+	if pos, ok := idx.attrs[n][key]; ok {
+		return pos
+	}
+
+	return idx.nodes[n]
+}
+
+// indexPositions finds the positions of the parsed nodes in the source.
+// The parser doesn't keep track of positions, so the source is scanned
+// in document order, in parallel with a walk of the node tree.
+func indexPositions(src string, nodes []*whtml.Node) *posIndex {
+	s := &posScanner{
+		src:   src,
+		lower: strings.ToLower(src),
+		idx: &posIndex{
+			nodes: map[*whtml.Node]srcPos{},
+			attrs: map[*whtml.Node]map[string]srcPos{},
+		},
+	}
+
+	s.lineStarts = []int{0}
+	for i, c := range src {
+		if c == '\n' {
+			s.lineStarts = append(s.lineStarts, i+1)
+		}
+	}
+
+	for _, n := range nodes {
+		s.walk(n)
+	}
+
+	return s.idx
+}
+
+type posScanner struct {
+	src, lower string
+	offset     int
+	lineStarts []int
+	idx        *posIndex
+}
+
+func (s *posScanner) pos(offset int) srcPos {
+	line := sort.Search(len(s.lineStarts), func(i int) bool {
+		return s.lineStarts[i] > offset
+	})
+
+	return srcPos{line: line, col: offset - s.lineStarts[line-1] + 1}
+}
+
+// next returns the offset of the next occurrence of pattern outside of comments, -1 if not found
+func (s *posScanner) next(pattern string) int {
+	from := s.offset
+	for {
+		i := strings.Index(s.lower[from:], pattern)
+		if i == -1 {
+			return -1
+		}
+
+		i += from
+		c := strings.Index(s.lower[from:i], "<!--")
+		if c == -1 {
+			return i
+		}
+
+		end := strings.Index(s.lower[from+c:], "-->")
+		if end == -1 {
+			return -1
+		}
+
+		from += c + end + 3
+	}
+}
+
+func (s *posScanner) walk(n *whtml.Node) {
+	switch n.Type {
+	case whtml.ElementNode:
+		s.element(n)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			s.walk(c)
+		}
+
+	case whtml.MustacheNode:
+		if i := s.next("{{"); i != -1 {
+			s.idx.nodes[n] = s.pos(i)
+			s.offset = i + 2
+			if end := strings.Index(s.src[s.offset:], "}}"); end != -1 {
+				s.offset += end + 2
+			}
+		}
+	}
+}
+
+func (s *posScanner) element(n *whtml.Node) {
+	tag := "<" + strings.ToLower(n.Data)
+	for {
+		i := s.next(tag)
+		if i == -1 {
+			return
+		}
+
+		s.offset = i + len(tag)
+		if s.offset < len(s.src) && !isTagNameChar(s.src[s.offset]) {
+			s.idx.nodes[n] = s.pos(i)
+			break
+		}
+	}
+
+	end := s.startTagEnd()
+	offsets := s.startTagAttrs(end)
+	attrs := map[string]srcPos{}
+	for _, attr := range n.Attrs {
+		if i, ok := offsets[strings.ToLower(attr.Key)]; ok {
+			attrs[attr.Key] = s.pos(i)
+		}
+	}
+
+	s.idx.attrs[n] = attrs
+	s.offset = end
+}
+
+func isTagNameChar(c byte) bool {
+	return c == ':' || isIdentByte(c)
+}
+
+func isIdentByte(c byte) bool {
+	return c == '-' || c == '_' ||
+		(c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// startTagEnd returns the offset right after the current start tag,
+// skipping quoted values and mustaches
+func (s *posScanner) startTagEnd() int {
+	var quote byte
+	for i := s.offset; i < len(s.src); i++ {
+		c := s.src[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(s.src[i:], "{{"):
+			end := strings.Index(s.src[i:], "}}")
+			if end == -1 {
+				return len(s.src)
+			}
+			i += end + 1
+		case c == '>':
+			return i + 1
+		}
+	}
+
+	return len(s.src)
+}
+
+// startTagAttrs returns the offsets of the attribute names of the current start tag,
+// keyed by lower-cased name
+func (s *posScanner) startTagAttrs(end int) map[string]int {
+	ret := map[string]int{}
+	i := s.offset
+	for i < end {
+		c := s.src[i]
+		switch {
+		case c == '"' || c == '\'':
+			if q := strings.IndexByte(s.src[i+1:end], c); q != -1 {
+				i += q + 2
+				continue
+			}
+			return ret
+		case strings.HasPrefix(s.src[i:end], "{{"):
+			if m := strings.Index(s.src[i:end], "}}"); m != -1 {
+				i += m + 2
+				continue
+			}
+			return ret
+		case c == '=':
+			// skip an unquoted value
+			i++
+			for i < end && s.src[i] == ' ' {
+				i++
+			}
+
+			if i < end && s.src[i] != '"' && s.src[i] != '\'' && !strings.HasPrefix(s.src[i:end], "{{") {
+				for i < end && strings.IndexByte(" \t\r\n>", s.src[i]) == -1 {
+					i++
+				}
+			}
+			continue
+		case isTagNameChar(c):
+			start := i
+			for i < end && isTagNameChar(s.src[i]) {
+				i++
+			}
+
+			name := s.lower[start:i]
+			if _, ok := ret[name]; !ok {
+				ret[name] = start
+			}
+			continue
+		}
+
+		i++
+	}
+
+	return ret
+}
+
+var (
+	markerRegex = regexp.MustCompile(`/\*fuel:(\d+|end)\*/`)
+)
+
+// addLineDirectives replaces the markers of a generated file with line directives,
+// so that compilers, debuggers and stack traces attribute the code of the marked
+// expressions and elements to their position in the markup file.
+// A second directive at the end marker restores the real position of the following code.
+func addLineDirectives(genPath string, file *htmlFile) error {
+	content, err := ioutil.ReadFile(genPath)
+	if err != nil {
+		return err
+	}
+
+	htmlName, genName := filepath.Base(file.path), filepath.Base(genPath)
+	var out bytes.Buffer
+	inSpan := false
+	for i, line := range strings.SplitAfter(string(content), "\n") {
+		lineStart := out.Len()
+		last := 0
+		for _, m := range markerRegex.FindAllStringSubmatchIndex(line, -1) {
+			out.WriteString(line[last:m[0]])
+			last = m[1]
+
+			id := line[m[2]:m[3]]
+			if id == "end" {
+				if inSpan {
+					out.WriteString(lineResetDirective(genName, i+1, out.Len()-lineStart))
+					inSpan = false
+				}
+
+				continue
+			}
+
+			idx, _ := strconv.Atoi(id)
+			if idx < len(file.exprs) && file.exprs[idx].pos.IsValid() {
+				pos := file.exprs[idx].pos
+				out.WriteString(sfmt("/*line %v:%v:%v*/", htmlName, pos.line, pos.col))
+				inSpan = true
+			}
+		}
+
+		out.WriteString(line[last:])
+	}
+
+	return ioutil.WriteFile(genPath, out.Bytes(), 0644)
+}
+
+// lineResetDirective returns a line directive that gives the code following it
+// its real position, col0 is the 0-based column where the directive is written
+func lineResetDirective(fileName string, line, col0 int) string {
+	col := col0 + 1
+	for {
+		d := sfmt("/*line %v:%v:%v*/", fileName, line, col)
+		if col == col0+len(d)+1 {
+			return d
+		}
+
+		col = col0 + len(d) + 1
+	}
+}
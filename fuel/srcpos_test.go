@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gowade/whtml"
+)
+
+func TestIndexPositions(t *testing.T) {
+	src := "<!-- <div> -->\n" +
+		"<Com>\n" +
+		"  <div class=\"hidden\" k=hidden hidden={{ this.Hidden }}>\n" +
+		"    Hi {{ this.Name }}\n" +
+		"  </div>\n" +
+		"</Com>\n"
+
+	com := &whtml.Node{Type: whtml.ElementNode, Data: "Com"}
+	div := &whtml.Node{Type: whtml.ElementNode, Data: "div", Parent: com, Attrs: []whtml.Attribute{
+		{Key: "class", Val: "hidden"},
+		{Key: "k", Val: "hidden"},
+		{Key: "hidden", Val: "this.Hidden", Type: whtml.MustacheAttribute},
+	}}
+	mustache := &whtml.Node{Type: whtml.MustacheNode, Data: "this.Name", Parent: div}
+	com.FirstChild, div.FirstChild = div, mustache
+
+	idx := indexPositions(src, []*whtml.Node{com})
+	cases := []struct {
+		pos      srcPos
+		expected string
+	}{
+		{idx.node(com), "2:1"},
+		{idx.node(div), "3:3"},
+		{idx.attr(div, "class"), "3:8"},
+		{idx.attr(div, "hidden"), "3:32"},
+		{idx.node(mustache), "4:8"},
+	}
+
+	for i, c := range cases {
+		if c.pos.String() != c.expected {
+			t.Errorf("case %v: expected %v, got %v", i, c.expected, c.pos)
+		}
+	}
+}
+
+func TestErrorPositions(t *testing.T) {
+	src := "<Page>\n" +
+		"  <input bind={{ this.Name }} t=\"Hello\">\n" +
+		"</Page>\n"
+
+	page := element("Page")
+	input := element("input", exprAttr("bind", "this.Name"), strAttr("t", "Hello"))
+	appendChildren(page, input)
+
+	z := testCompiler(nil)
+	z.comName = "Page"
+	z.htmlFile.path = "/app/page.whtml"
+	z.htmlFile.positions = indexPositions(src, []*whtml.Node{page})
+
+	cases := []struct {
+		err      error
+		expected string
+	}{
+		{z.nodeError(input, efmt("oops")), "/app/page.whtml:2:3: oops"},
+		{z.attrError(input, "t", efmt("oops")), "/app/page.whtml:2:31: oops"},
+		{z.transError(input, efmt("oops")), "/app/page.whtml:2:31: oops"},
+		{z.bindError(input, "oops"), "/app/page.whtml:2:10: "},
+		{z.nodeError(element("div"), efmt("oops")), "/app/page.whtml: oops"},
+	}
+
+	for i, c := range cases {
+		if !strings.HasPrefix(c.err.Error(), c.expected) {
+			t.Errorf("case %v: expected %v, got %v", i, c.expected, c.err)
+		}
+	}
+}
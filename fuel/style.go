@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bytes"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gowade/whtml"
+)
+
+const (
+	styleSTag       = "style"
+	styleBundleFile = "components.css"
+	scopeAttrPrefix = "data-w-"
+)
+
+// scopedAtRules are the at-rules whose blocks contain style rules
+var scopedAtRules = []string{"@media", "@supports", "@container", "@layer", "@document"}
+
+// scopeAttrName returns the attribute that marks the elements of a component, it depends
+// on the import path of the component's package, its file name and its name so that it's
+// the same on every machine, the generated code of packages in the module cache keeps
+// the attributes it was generated with
+func scopeAttrName(filePath, comName string) string {
+	h := fnv.New32a()
+	h.Write([]byte(pkgImportPath(filepath.Dir(filePath)) + "/" + filepath.Base(filePath) + "/" + comName))
+	return sfmt("%v%08x", scopeAttrPrefix, h.Sum32())
+}
+
+// extractStyles removes the style elements from a component definition
+// and returns their content
+func extractStyles(def *whtml.Node) (string, error) {
+	var css bytes.Buffer
+	var prev *whtml.Node
+	for c := def.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != whtml.ElementNode || c.Data != styleSTag {
+			prev = c
+			continue
+		}
+
+		for t := c.FirstChild; t != nil; t = t.NextSibling {
+			if t.Type != whtml.TextNode {
+				return "", efmt("%v: <style> can only contain CSS", def.Data)
+			}
+
+			css.WriteString(t.Data)
+		}
+		css.WriteString("\n")
+
+		if prev == nil {
+			def.FirstChild = c.NextSibling
+		} else {
+			prev.NextSibling = c.NextSibling
+		}
+
+		if c.NextSibling != nil {
+			c.NextSibling.PrevSibling = prev
+		}
+
+		if c == def.LastChild {
+			def.LastChild = prev
+		}
+	}
+
+	return css.String(), nil
+}
+
+// skipString returns the index after the quoted string that starts at s[i]
+func skipString(s string, i int) int {
+	quote := s[i]
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		}
+	}
+
+	return len(s)
+}
+
+// skipComment returns the index after the comment that starts at s[i]
+func skipComment(s string, i int) int {
+	end := strings.Index(s[i+2:], "*/")
+	if end == -1 {
+		return len(s)
+	}
+
+	return i + 2 + end + 2
+}
+
+func isCommentStart(s string, i int) bool {
+	return s[i] == '/' && i+1 < len(s) && s[i+1] == '*'
+}
+
+func stripComments(s string) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); {
+		switch {
+		case isCommentStart(s, i):
+			i = skipComment(s, i)
+		case s[i] == '"' || s[i] == '\'':
+			end := skipString(s, i)
+			buf.WriteString(s[i:end])
+			i = end
+		default:
+			buf.WriteByte(s[i])
+			i++
+		}
+	}
+
+	return buf.String()
+}
+
+// blockEnd returns the index after the } that closes the block starting at s[i]
+func blockEnd(s string, i int) (int, error) {
+	depth := 0
+	for i < len(s) {
+		switch {
+		case isCommentStart(s, i):
+			i = skipComment(s, i)
+			continue
+		case s[i] == '"' || s[i] == '\'':
+			i = skipString(s, i)
+			continue
+		case s[i] == '{':
+			depth++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+
+		i++
+	}
+
+	return 0, efmt("unclosed CSS block")
+}
+
+// scopeCSS adds the scope attribute to the selectors of the style rules
+func scopeCSS(css, attr string) (string, error) {
+	var out bytes.Buffer
+	for i := 0; i < len(css); {
+		// the prelude of a rule ends at its block, or at the ; of an at-rule statement
+		j := i
+	prelude:
+		for j < len(css) {
+			switch {
+			case isCommentStart(css, j):
+				j = skipComment(css, j)
+				continue
+			case css[j] == '"' || css[j] == '\'':
+				j = skipString(css, j)
+				continue
+			case css[j] == '{' || css[j] == ';':
+				break prelude
+			case css[j] == '}':
+				return "", efmt("unexpected } in CSS")
+			}
+
+			j++
+		}
+
+		prelude := css[i:j]
+		if j == len(css) {
+			out.WriteString(prelude)
+			break
+		}
+
+		if css[j] == ';' {
+			out.WriteString(css[i : j+1])
+			i = j + 1
+			continue
+		}
+
+		end, err := blockEnd(css, j)
+		if err != nil {
+			return "", err
+		}
+
+		body := css[j+1 : end-1]
+		p := strings.TrimSpace(stripComments(prelude))
+		space := prelude[:len(prelude)-len(strings.TrimLeft(prelude, " \t\r\n"))]
+		switch {
+		case isScopedAtRule(p):
+			scoped, err := scopeCSS(body, attr)
+			if err != nil {
+				return "", err
+			}
+
+			out.WriteString(space + p + " {" + scoped + "}")
+
+		case strings.HasPrefix(p, "@"):
+			// @keyframes, @font-face...
+			out.WriteString(css[i:end])
+
+		default:
+			out.WriteString(space + scopeSelectors(p, attr) + " {" + body + "}")
+		}
+
+		i = end
+	}
+
+	return out.String(), nil
+}
+
+func isScopedAtRule(prelude string) bool {
+	for _, rule := range scopedAtRules {
+		if strings.HasPrefix(prelude, rule) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// scopeSelectors adds the scope attribute to each selector of a list
+func scopeSelectors(list, attr string) string {
+	var selectors []string
+	depth, start := 0, 0
+	for i := 0; i < len(list); i++ {
+		switch list[i] {
+		case '\\':
+			i++
+		case '"', '\'':
+			i = skipString(list, i) - 1
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				selectors = append(selectors, scopeSelector(list[start:i], attr))
+				start = i + 1
+			}
+		}
+	}
+
+	selectors = append(selectors, scopeSelector(list[start:], attr))
+	return strings.Join(selectors, ", ")
+}
+
+// scopeSelector adds the scope attribute to the last compound selector of a selector,
+// before its pseudo-classes and pseudo-elements: ".list li:hover" becomes ".list li[attr]:hover"
+func scopeSelector(sel, attr string) string {
+	sel = strings.TrimSpace(sel)
+
+	// the last compound starts after the last combinator
+	depth, start := 0, 0
+	for i := 0; i < len(sel); i++ {
+		switch sel[i] {
+		case '\\':
+			i++
+		case '"', '\'':
+			i = skipString(sel, i) - 1
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ' ', '\t', '\r', '\n', '>', '+', '~':
+			if depth == 0 {
+				start = i + 1
+			}
+		}
+	}
+
+	compound := sel[start:]
+	pos := len(compound)
+	depth = 0
+loop:
+	for i := 0; i < len(compound); i++ {
+		switch compound[i] {
+		case '\\':
+			i++
+		case '"', '\'':
+			i = skipString(compound, i) - 1
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ':':
+			if depth == 0 {
+				pos = i
+				break loop
+			}
+		}
+	}
+
+	return sel[:start] + compound[:pos] + "[" + attr + "]" + compound[pos:]
+}
+
+// pkgStyles returns the scoped CSS of the components of a package and its dependencies
+func pkgStyles(pkg *fuelPkg, visited map[string]bool, styles map[string]string) error {
+	if pkg == nil || visited[pkg.dir] {
+		return nil
+	}
+
+	visited[pkg.dir] = true
+	for _, file := range pkg.htmlFiles {
+		for _, com := range file.comDefs {
+			if com.style == "" {
+				continue
+			}
+
+			css, err := scopeCSS(com.style, com.scope)
+			if err != nil {
+				return efmt("%v: %v: %v", file.path, com.name, err)
+			}
+
+			styles[com.scope] = sfmt("/* %v */\n%v\n", com.name, strings.TrimSpace(css))
+		}
+	}
+
+	for _, imp := range pkg.imports {
+		if err := pkgStyles(imp, visited, styles); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range pkg.htmlFiles {
+		for _, imp := range file.imports {
+			if err := pkgStyles(imp.fuelPkg, visited, styles); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeStyleBundle writes the scoped CSS of the components of a package
+// and its dependencies to a file, if there's none the file is only created
+// when it exists already, so that a stale bundle doesn't stay around
+func writeStyleBundle(pkg *fuelPkg, filePath string) error {
+	styles := make(map[string]string)
+	if err := pkgStyles(pkg, map[string]bool{}, styles); err != nil {
+		return err
+	}
+
+	if len(styles) == 0 {
+		if _, err := os.Stat(filePath); err != nil {
+			return nil
+		}
+	}
+
+	scopes := make([]string, 0, len(styles))
+	for scope := range styles {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	var buf bytes.Buffer
+	buf.WriteString("/* THIS FILE IS AUTOGENERATED BY WADE.GO FUEL */\n\n")
+	for _, scope := range scopes {
+		buf.WriteString(styles[scope])
+		buf.WriteString("\n")
+	}
+
+	return ioutil.WriteFile(filePath, buf.Bytes(), 0644)
+}
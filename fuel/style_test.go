@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScopeCSS(t *testing.T) {
+	css := `
+/* list */
+.list li:hover, .list > a::before { color: red; }
+@media (max-width: 600px) {
+	div.item[title="a,b c"] { margin: 0 }
+}
+@keyframes spin { from { transform: rotate(0) } }
+@import "base.css";
+.sm\:hidden { display: none }
+`
+
+	expected := `
+.list li[data-w-x]:hover, .list > a[data-w-x]::before { color: red; }
+@media (max-width: 600px) {
+	div.item[title="a,b c"][data-w-x] { margin: 0 }
+}
+@keyframes spin { from { transform: rotate(0) } }
+@import "base.css";
+.sm\:hidden[data-w-x] { display: none }
+`
+
+	scoped, err := scopeCSS(css, "data-w-x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if scoped != expected {
+		t.Errorf("expected\n%v\ngot\n%v", expected, scoped)
+	}
+
+	if _, err := scopeCSS(".a { color: red", "data-w-x"); err == nil {
+		t.Error("expected an error for an unclosed block")
+	}
+}
+
+func TestExtractStyles(t *testing.T) {
+	div := element("div")
+	def := appendChildren(element("Card"),
+		appendChildren(element(styleSTag), textNode(".a { color: red }")),
+		div,
+		appendChildren(element(styleSTag), textNode(".b { color: blue }")))
+
+	css, err := extractStyles(def)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if css != ".a { color: red }\n.b { color: blue }\n" {
+		t.Errorf("unexpected CSS %q", css)
+	}
+
+	if def.FirstChild != div || def.LastChild != div || div.PrevSibling != nil || div.NextSibling != nil {
+		t.Errorf("expected the style elements to be removed")
+	}
+
+	def = appendChildren(element("Card"), appendChildren(element(styleSTag), element("p")))
+	_, err = extractStyles(def)
+	checkErr(t, "element in style", err, "can only contain CSS")
+}
+
+func TestScopeAttrName(t *testing.T) {
+	comFile := func(module string) string {
+		root, err := ioutil.TempDir("", "fuelscope")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.RemoveAll(root) })
+
+		dir := filepath.Join(root, "a")
+		if err := os.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filepath.Join(root, "go.mod"), []byte("module "+module+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		return filepath.Join(dir, "components.whtml")
+	}
+
+	file, otherRoot := comFile("example.com/app"), comFile("example.com/app")
+	a := scopeAttrName(file, "Card")
+	if b := scopeAttrName(otherRoot, "Card"); a != b {
+		t.Errorf("expected the same scope attribute from another root, got %v and %v", a, b)
+	}
+
+	for _, other := range []string{
+		scopeAttrName(file, "List"),
+		scopeAttrName(comFile("example.com/lib"), "Card"),
+	} {
+		if other == a {
+			t.Errorf("expected distinct scope attributes, got %v twice", a)
+		}
+	}
+}
+
+func TestPkgImportPath(t *testing.T) {
+	if p := unescapeModPath("github.com/!user/lib@v1.0.0/x"); p != "github.com/User/lib@v1.0.0/x" {
+		t.Errorf("unexpected unescaped path %v", p)
+	}
+
+	if p := modulePath([]byte("// app\nmodule \"example.com/app\"\n\ngo 1.16\n")); p != "example.com/app" {
+		t.Errorf("unexpected module path %v", p)
+	}
+}
+
+func TestScopeAttrInjected(t *testing.T) {
+	z := testCompiler(nil)
+	z.scope = "data-w-x"
+
+	var buf bytes.Buffer
+	if err := z.elementGenerate(&buf, element("div", strAttr("class", "a")), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := buf.String(); !strings.Contains(code, `"data-w-x"`) {
+		t.Errorf("expected the scope attribute in %v", code)
+	}
+}
+
+func TestStaleStyleBundle(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "fuelstyle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	bundle := filepath.Join(tmp, styleBundleFile)
+	if err := ioutil.WriteFile(bundle, []byte(".a[data-w-x] { color: red }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := testPkg(t, tmp, "", nil)
+	if err := writeStyleBundle(pkg, bundle); err != nil {
+		t.Fatal(err)
+	}
+
+	css, err := ioutil.ReadFile(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(css), "data-w-x") {
+		t.Errorf("expected the stale styles to be removed, got %s", css)
+	}
+}
@@ -48,6 +48,8 @@ type (
 		Decls            *bytes.Buffer
 		ComName, ComType string
 		FieldsAss        []fieldAssTD
+		Mark             string
+		Parent           string
 
 		ChildrenCode []childCode
 	}
@@ -65,6 +67,7 @@ type (
 
 	elementVDOMTD struct {
 		Tag      string
+		Mark     string
 		Key      string
 		Attrs    map[string]string
 		Children []childCode
@@ -103,7 +106,7 @@ const (
 		`}[[else]]nil[[end]]` +
 		`[[end]]` +
 
-		`vdom.NewElement("[[.Tag]]", wade.Str([[.Key]]), [[template "attrs" .]],` +
+		`[[.Mark]]vdom.NewElement("[[.Tag]]"[[endMark .Mark]], wade.Str([[.Key]]), [[template "attrs" .]],` +
 		`[[template "children" .Children]])`
 
 	renderFuncCode = `
@@ -146,11 +149,15 @@ func init() {
 [[end]]
 
 func (this [[$receiver]]) VDOMChildren() []vdom.VNode {
-	return vdom.GetComponentData(this).Children
+	return wade.SlotContent(vdom.GetComponentData(this).Children, "")
+}
+
+func (this [[$receiver]]) VDOMSlot(name string) []vdom.VNode {
+	return wade.SlotContent(vdom.GetComponentData(this).Children, name)
 }
 
 func (this [[$receiver]]) rerender() {
-	vdom.RerenderComponent(this)
+	wade.RerenderComponent(this)
 }
 `
 
@@ -185,7 +192,7 @@ func (this *[[.ComName]]) Refs() (ret *[[.ComName]]Refs) {
 		[[end]]
 		[[.Decls]]
 
-		return vdom.RenderComponent(com, [[template "children" .ChildrenCode]])
+		return [[.Mark]]wade.RenderChildComponent([[.Parent]], com[[endMark .Mark]], [[template "children" .ChildrenCode]])
 	},
 }`
 
@@ -203,6 +210,13 @@ var (
 		"lastIdx": func(l []childCode) int {
 			return len(l) - 1
 		},
+		"endMark": func(mark string) string {
+			if mark == "" {
+				return ""
+			}
+
+			return exprMarkerEnd
+		},
 		"elDOMType": func(elTag string) string {
 			switch elTag {
 			case "input":
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gowade/whtml"
+)
+
+const (
+	exprMarkerEnd = "/*fuel:end*/"
+)
+
+var (
+	exprMarkerRegex = regexp.MustCompile(`^/\*fuel:(\d+)\*/$`)
+)
+
+// exprOrigin tells where a Go expression in the generated code was written in the markup
+type exprOrigin struct {
+	comName string
+	elTag   string
+	attr    string // empty for a mustache inside a text node
+	expr    string // empty for the element itself
+	pos     srcPos
+}
+
+func (o exprOrigin) String() string {
+	loc := sfmt("<%v>", o.elTag)
+	switch {
+	case o.attr != "":
+		loc = sfmt("%v attribute '%v' {{ %v }}", loc, o.attr, o.expr)
+	case o.expr != "":
+		loc = sfmt("%v text {{ %v }}", loc, o.expr)
+	}
+
+	if o.comName != "" {
+		loc = o.comName + ": " + loc
+	}
+
+	return loc
+}
+
+// exprCode records the origin of a Go expression written in the markup and
+// returns its code surrounded by markers, so that errors in the generated code
+// can be reported at the right place
+func (z *htmlCompiler) exprCode(el *whtml.Node, attr string, expr string, code string, pos srcPos) string {
+	return z.addOrigin(el, attr, expr, pos) + code + exprMarkerEnd
+}
+
+// addOrigin records an origin and returns the start marker for it
+func (z *htmlCompiler) addOrigin(el *whtml.Node, attr string, expr string, pos srcPos) string {
+	var elTag string
+	if el != nil {
+		elTag = el.Data
+	}
+
+	z.htmlFile.exprs = append(z.htmlFile.exprs, exprOrigin{
+		comName: z.comName,
+		elTag:   elTag,
+		attr:    attr,
+		expr:    expr,
+		pos:     pos,
+	})
+
+	return sfmt("/*fuel:%v*/", len(z.htmlFile.exprs)-1)
+}
+
+// elementMark returns the start marker for the code that creates an element,
+// it is empty if the element's position is unknown
+func (z *htmlCompiler) elementMark(el *whtml.Node) string {
+	pos := z.htmlFile.positions.node(el)
+	if !pos.IsValid() {
+		return ""
+	}
+
+	return z.addOrigin(el, "", "", pos)
+}
+
+// attrCode returns the Go code for the value of an element's attribute
+func (z *htmlCompiler) attrCode(el *whtml.Node, attr whtml.Attribute) string {
+	code := attributeValueCode(attr)
+	pos := z.htmlFile.positions.attr(el, attr.Key)
+	switch {
+	case attr.Type == whtml.MustacheAttribute:
+		return z.exprCode(el, attr.Key, attr.Val, code, pos)
+	case attr.Type == whtml.StringAttribute && len(attr.Mustaches) > 0:
+		return z.exprCode(el, attr.Key, strings.Join(attr.Mustaches, ", "), code, pos)
+	}
+
+	return code
+}
+
+// mustacheCode returns the Go code for the value of a mustache node
+func (z *htmlCompiler) mustacheCode(node *whtml.Node) string {
+	return valueToStrCode(z.exprCode(node.Parent, "", node.Data, node.Data,
+		z.htmlFile.positions.node(node)))
+}
+
+type exprSpan struct {
+	start, end token.Pos
+	origin     exprOrigin
+}
+
+// exprSpans finds the marked expressions in a generated file
+func exprSpans(file *ast.File, exprs []exprOrigin) []exprSpan {
+	var spans []exprSpan
+	var cur *exprSpan
+	for _, cg := range file.Comments {
+		for _, c := range cg.List {
+			if c.Text == exprMarkerEnd && cur != nil {
+				cur.end = c.Pos()
+				spans = append(spans, *cur)
+				cur = nil
+				continue
+			}
+
+			m := exprMarkerRegex.FindStringSubmatch(c.Text)
+			if m == nil {
+				continue
+			}
+
+			idx, _ := strconv.Atoi(m[1])
+			if idx < len(exprs) {
+				cur = &exprSpan{
+					start:  c.End(),
+					origin: exprs[idx],
+				}
+			}
+		}
+	}
+
+	return spans
+}
+
+// typeCheckPkg type-checks a package with its generated code, errors in the
+// generated files are reported against the markup that produced them
+func typeCheckPkg(pkg *fuelPkg) error {
+	errs, err := typeErrors(pkg)
+	if err != nil || len(errs) == 0 {
+		return err
+	}
+
+	for _, err := range errs {
+		printErr(err)
+	}
+
+	return efmt("%v: %v type error(s) in components", pkg.dir, len(errs))
+}
+
+// typeErrors returns the type errors in the generated files of a package, found from
+// the markers around the marked expressions, they must be checked before the markers
+// are replaced by line directives
+func typeErrors(pkg *fuelPkg) ([]error, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, pkg.dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	astPkg := pkgs[pkg.pkg.Name]
+	if astPkg == nil {
+		return nil, nil
+	}
+
+	var files []*ast.File
+	genFiles := make(map[string]*ast.File)
+	for name, file := range astPkg.Files {
+		files = append(files, file)
+		if isFuelFile(name) {
+			genFiles[name] = file
+		}
+	}
+
+	var typeErrs []types.Error
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			if terr, ok := err.(types.Error); ok {
+				typeErrs = append(typeErrs, terr)
+			}
+		},
+	}
+	conf.Check(pkg.pkg.Name, fset, files, nil)
+
+	for _, terr := range typeErrs {
+		// the package or one of its dependencies has problems of its own
+		// that will be reported by the go tool, the check is not reliable
+		if strings.HasPrefix(terr.Msg, "could not import") {
+			printErr(efmt("%v: skipped type checking: %v", pkg.dir, terr.Msg))
+			return nil, nil
+		}
+	}
+
+	var errs []error
+	for _, file := range pkg.htmlFiles {
+		genPath := componentVDOMFilePath(file)
+		genFile := genFiles[genPath]
+		if genFile == nil {
+			continue
+		}
+
+		spans := exprSpans(genFile, file.exprs)
+		for _, terr := range typeErrs {
+			// positions without line directives applied
+			pos := fset.PositionFor(terr.Pos, false)
+			if pos.Filename != genPath {
+				continue
+			}
+
+			errs = append(errs, typeErrorAt(file, spans, terr, pos))
+		}
+	}
+
+	return errs, nil
+}
+
+func typeErrorAt(file *htmlFile, spans []exprSpan, terr types.Error, pos token.Position) error {
+	for _, span := range spans {
+		if terr.Pos >= span.start && terr.Pos < span.end {
+			if span.origin.pos.IsValid() {
+				return efmt("%v:%v: %v: %v", file.path, span.origin.pos, span.origin, terr.Msg)
+			}
+
+			return efmt("%v: %v: %v", file.path, span.origin, terr.Msg)
+		}
+	}
+
+	return efmt("%v (generated from %v): %v",
+		pos, filepath.Base(file.path), terr.Msg)
+}
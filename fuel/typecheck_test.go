@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTypeErrors(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "fueltypes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	src := "package app\n\ntype Card struct {\n\tCount int\n}\n"
+	pkg := testPkg(t, tmp, src, nil)
+	file := pkg.htmlFiles[0]
+	file.exprs = []exprOrigin{{
+		comName: "Card",
+		elTag:   "p",
+		attr:    "title",
+		expr:    "this.Count",
+		pos:     srcPos{line: 3, col: 12},
+	}}
+
+	gen := "package app\n\n" +
+		"func (this *Card) title() string {\n" +
+		"\treturn /*fuel:0*/ this.Count /*fuel:end*/\n" +
+		"}\n"
+
+	genPath := componentVDOMFilePath(file)
+	for path, content := range map[string]string{filepath.Join(tmp, "app.go"): src, genPath: gen} {
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs, err := typeErrors(pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := file.path + ":3:12: Card: <p> attribute 'title' {{ this.Count }}: cannot use"
+	if len(errs) != 1 || !strings.HasPrefix(errs[0].Error(), expected) {
+		t.Fatalf("expected an error at the attribute, got %v", errs)
+	}
+
+	if err := addLineDirectives(genPath, file); err != nil {
+		t.Fatal(err)
+	}
+
+	code, err := ioutil.ReadFile(genPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(code), "/*fuel:") ||
+		!strings.Contains(string(code), "/*line app.whtml:3:12*/") {
+		t.Errorf("expected the markers to be replaced by line directives, got\n%s", code)
+	}
+}
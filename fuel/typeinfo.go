@@ -90,7 +90,7 @@ func (p *astPkg) lookupSelector(file *ast.File, selector *ast.SelectorExpr) (
 	for _, imp := range file.Imports {
 		if importName(imp) == ident.Name {
 			impPath := importPath(imp)
-			pdir := importDir(impPath)
+			pdir := importDir(p.pkg.dir, impPath)
 			if pdir != "" {
 				pkg := p.pkgs[impPath]
 				if pkg != nil {
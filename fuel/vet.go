@@ -0,0 +1,673 @@
+package main
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gowade/whtml"
+)
+
+// checks of fuel vet
+const (
+	vetAttrs     = "attrs"     // unknown attributes of special tags
+	vetForKey    = "forkey"    // elements repeated by a for loop without a key
+	vetRefs      = "refs"      // refs declared twice in a component
+	vetUnusedCom = "unusedcom" // components of a program that are never used
+	vetImports   = "imports"   // unused import tags
+	vetHandlers  = "handlers"  // event handlers bound to values that aren't functions
+)
+
+// vetIssue is a problem found by fuel vet
+type vetIssue struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Col     int    `json:"col"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+func (i vetIssue) String() string {
+	if i.Line == 0 {
+		return sfmt("%v: %v", i.File, i.Message)
+	}
+
+	return sfmt("%v:%v:%v: %v", i.File, i.Line, i.Col, i.Message)
+}
+
+// specialTagAttrs are the attributes that each special tag accepts
+var specialTagAttrs = map[string][]string{
+	importSTag:  {"from", "as"},
+	forSTag:     {"k", "v", "range"},
+	ifSTag:      {"cond"},
+	switchSTag:  {"expr"},
+	caseSTag:    {"expr"},
+	defaultSTag: {},
+	renderSTag:  {"content"},
+	slotSTag:    {"name", "required"},
+}
+
+// comDefPos is the definition of a component in a vetted file
+type comDefPos struct {
+	key  string // package dir and name, the key of vetter.used
+	dir  string
+	name string
+	file string
+	pos  srcPos
+}
+
+type vetter struct {
+	issues   []vetIssue
+	roots    map[string]bool // dirs of the packages under the vetted directory
+	mains    map[string]bool // dirs of the main packages under the vetted directory
+	vetted   map[string]bool // dirs of the vetted packages
+	imported map[string]bool // dirs of the packages imported by vetted packages
+	used     map[string]bool // components created in markup or Go code, keyed by package dir and name
+	defs     []comDefPos
+}
+
+func newVetter() *vetter {
+	return &vetter{
+		roots:    map[string]bool{},
+		mains:    map[string]bool{},
+		vetted:   map[string]bool{},
+		imported: map[string]bool{},
+		used:     map[string]bool{},
+	}
+}
+
+// vetFile holds the state of the checks of a markup file
+type vetFile struct {
+	*vetter
+	pkg       *fuelPkg
+	path      string
+	imports   map[string]importedPkg
+	positions *posIndex
+
+	comName     string                 // the component being checked
+	refs        map[string]*whtml.Node // refs declared in the component
+	usedImports map[string]bool
+}
+
+func (f *vetFile) report(check string, pos srcPos, format string, args ...interface{}) {
+	f.issues = append(f.issues, vetIssue{
+		File:    f.path,
+		Line:    pos.line,
+		Col:     pos.col,
+		Check:   check,
+		Message: sfmt(format, args...),
+	})
+}
+
+// vetDir checks the markup of the packages under dir and of their dependencies
+// that fuel builds, the file paths of the issues are relative to dir
+func vetDir(dir string) ([]vetIssue, error) {
+	var roots []*fuelPkg
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			return nil
+		}
+
+		// the directories that the go tool ignores
+		name := info.Name()
+		if path != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+			name == "vendor" || name == "testdata") {
+			return filepath.SkipDir
+		}
+
+		pkg, err := getFuelPkg(path)
+		if err != nil {
+			return efmt("%v: %v", path, err)
+		}
+
+		if pkg != nil {
+			roots = append(roots, pkg)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	v := newVetter()
+	for _, pkg := range roots {
+		v.addRoot(pkg)
+	}
+
+	for _, pkg := range roots {
+		if err := v.vetPkg(pkg); err != nil {
+			return nil, err
+		}
+	}
+
+	v.reportUnused()
+
+	issues := v.issues
+	for i := range issues {
+		if rel, err := filepath.Rel(dir, issues[i].File); err == nil && !strings.HasPrefix(rel, "..") {
+			issues[i].File = rel
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		return a.Col < b.Col
+	})
+
+	return issues, nil
+}
+
+// vetPkg checks a package and the dependencies that fuel builds
+func (v *vetter) vetPkg(pkg *fuelPkg) error {
+	if v.vetted[pkg.dir] {
+		return nil
+	}
+
+	v.vetted[pkg.dir] = true
+	v.goUses(pkg)
+
+	for _, hf := range pkg.htmlFiles {
+		if err := v.vetHTMLFile(pkg, hf); err != nil {
+			return err
+		}
+	}
+
+	var deps []*fuelPkg
+	for _, dep := range pkg.imports {
+		deps = append(deps, dep)
+	}
+
+	for _, hf := range pkg.htmlFiles {
+		for _, imp := range hf.imports {
+			deps = append(deps, imp.fuelPkg)
+		}
+	}
+
+	for _, dep := range deps {
+		if dep != nil && dep.dir != pkg.dir {
+			v.imported[dep.dir] = true
+		}
+
+		if dep != nil && dep.HasMarkup() && !inModCache(dep.dir) {
+			if err := v.vetPkg(dep); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// goUses records the components used in the Go code of a package
+func (v *vetter) goUses(pkg *fuelPkg) {
+	for _, file := range pkg.pkg.Files {
+		dotDirs := []string{pkg.dir}
+		imports := make(map[string]string)
+		for _, imp := range file.Imports {
+			pdir := importDir(pkg.dir, importPath(imp))
+			if pdir == "" {
+				continue
+			}
+
+			if imp.Name != nil && imp.Name.Name == "." {
+				dotDirs = append(dotDirs, pdir)
+			} else {
+				imports[importName(imp)] = pdir
+			}
+		}
+
+		var visit func(ast.Node) bool
+		inspect := func(n ast.Node) {
+			if n != nil {
+				ast.Inspect(n, visit)
+			}
+		}
+
+		visit = func(node ast.Node) bool {
+			switch n := node.(type) {
+			case *ast.FuncDecl:
+				// a method is not a use of its receiver
+				inspect(n.Type)
+				inspect(n.Body)
+				return false
+
+			case *ast.TypeSpec:
+				inspect(n.Type)
+				return false
+
+			case *ast.Field:
+				inspect(n.Type)
+				return false
+
+			case *ast.KeyValueExpr:
+				if _, ok := n.Key.(*ast.Ident); !ok {
+					inspect(n.Key)
+				}
+
+				inspect(n.Value)
+				return false
+
+			case *ast.SelectorExpr:
+				if id, ok := n.X.(*ast.Ident); ok {
+					if pdir, ok := imports[id.Name]; ok {
+						v.used[pdir+"."+n.Sel.Name] = true
+						return false
+					}
+				}
+
+				inspect(n.X)
+				return false
+
+			case *ast.Ident:
+				for _, dir := range dotDirs {
+					v.used[dir+"."+n.Name] = true
+				}
+			}
+
+			return true
+		}
+
+		for _, decl := range file.Decls {
+			inspect(decl)
+		}
+	}
+}
+
+func (v *vetter) vetHTMLFile(pkg *fuelPkg, hf *htmlFile) error {
+	src, err := ioutil.ReadFile(hf.path)
+	if err != nil {
+		return err
+	}
+
+	nodes, err := whtml.Parse(bytes.NewReader(src))
+	if err != nil {
+		return efmt("%v: %v", hf.path, err)
+	}
+
+	f := &vetFile{
+		vetter:    v,
+		pkg:       pkg,
+		path:      hf.path,
+		imports:   hf.imports,
+		positions: indexPositions(string(src), nodes),
+	}
+
+	f.vetNodes(nodes)
+	return nil
+}
+
+// vetNodes checks the top-level nodes of a markup file
+func (f *vetFile) vetNodes(nodes []*whtml.Node) {
+	f.usedImports = make(map[string]bool)
+	importTags := make(map[string]*whtml.Node)
+	for _, n := range nodes {
+		if n.Type != whtml.ElementNode || n.Data == "" {
+			continue
+		}
+
+		switch {
+		case n.Data == importSTag:
+			f.checkSpecialAttrs(n)
+			for _, attr := range n.Attrs {
+				if attr.Key == "as" && attr.Val != "" {
+					importTags[attr.Val] = n
+				}
+			}
+
+		case isCapitalized(n.Data):
+			f.defs = append(f.defs, comDefPos{
+				key:  f.pkg.dir + "." + n.Data,
+				dir:  f.pkg.dir,
+				name: n.Data,
+				file: f.path,
+				pos:  f.positions.node(n),
+			})
+
+			f.comName = n.Data
+			f.refs = make(map[string]*whtml.Node)
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				f.walk(c)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(importTags))
+	for name := range importTags {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	for _, name := range names {
+		if !f.usedImports[name] {
+			n := importTags[name]
+			f.report(vetImports, f.positions.node(n), "import %v is not used", name)
+		}
+	}
+}
+
+// walk checks a node of a component's markup and its descendants
+func (f *vetFile) walk(n *whtml.Node) {
+	if n.Type == whtml.MustacheNode {
+		f.useExpr(n.Data)
+	}
+
+	if n.Type != whtml.ElementNode || n.Data == "" {
+		return
+	}
+
+	for _, attr := range n.Attrs {
+		switch {
+		case attr.Type == whtml.MustacheAttribute:
+			f.useExpr(attr.Val)
+		case attr.Type == whtml.StringAttribute:
+			for _, m := range attr.Mustaches {
+				f.useExpr(m)
+			}
+		}
+
+		if attr.Key == RefAttrName {
+			f.checkRef(n, attr)
+		}
+	}
+
+	_, slotFill := slotFillName(n)
+	switch {
+	case f.isSpecialTag(n):
+		f.checkSpecialAttrs(n)
+		if n.Data == forSTag {
+			f.checkForKeys(n)
+		}
+
+	case slotFill:
+		// the content of a slot is checked with the children
+
+	case strings.Contains(n.Data, ":") || isCapitalized(n.Data):
+		f.comUse(n)
+
+	default:
+		for _, attr := range n.Attrs {
+			if isEventAttr(attr.Key) {
+				f.checkHandler(n, attr)
+			}
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		f.walk(c)
+	}
+}
+
+// isSpecialTag tells whether an element of a component's markup is a special tag,
+// case and default tags are only special inside a switch
+func (f *vetFile) isSpecialTag(n *whtml.Node) bool {
+	switch n.Data {
+	case importSTag:
+		return false
+	case caseSTag, defaultSTag:
+		return n.Parent != nil && n.Parent.Data == switchSTag
+	}
+
+	_, ok := specialTagAttrs[n.Data]
+	return ok
+}
+
+func (f *vetFile) checkSpecialAttrs(n *whtml.Node) {
+	for _, attr := range n.Attrs {
+		if !strListContains(specialTagAttrs[n.Data], attr.Key) {
+			msg := sfmt("unknown attribute %v of the %v tag", attr.Key, n.Data)
+			if accepted := specialTagAttrs[n.Data]; len(accepted) > 0 {
+				msg += sfmt(" (accepted: %v)", strings.Join(accepted, ", "))
+			}
+
+			f.report(vetAttrs, f.positions.attr(n, attr.Key), "%v", msg)
+		}
+	}
+}
+
+// checkForKeys checks that the elements repeated by a for loop have a key,
+// looking through the control tags inside it
+func (f *vetFile) checkForKeys(n *whtml.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != whtml.ElementNode || c.Data == "" {
+			continue
+		}
+
+		_, slotFill := slotFillName(c)
+		switch {
+		case c.Data == ifSTag || c.Data == switchSTag ||
+			((c.Data == caseSTag || c.Data == defaultSTag) && n.Data == switchSTag):
+			f.checkForKeys(c)
+
+		case f.isSpecialTag(c) || slotFill ||
+			strings.Contains(c.Data, ":") || isCapitalized(c.Data):
+			// keys are only given to elements
+
+		default:
+			hasKey := false
+			for _, attr := range c.Attrs {
+				hasKey = hasKey || attr.Key == keyAttrName
+			}
+
+			if !hasKey {
+				f.report(vetForKey, f.positions.node(c),
+					"<%v> is repeated by a for loop but has no key attribute", c.Data)
+			}
+		}
+	}
+}
+
+func (f *vetFile) checkRef(n *whtml.Node, attr whtml.Attribute) {
+	name := refNameFromAttr(attr.Val)
+	if prev, ok := f.refs[name]; ok {
+		msg := sfmt("ref %v is declared twice in %v", attr.Val, f.comName)
+		if pos := f.positions.attr(prev, RefAttrName); pos.IsValid() {
+			msg += sfmt(", first declared at %v", pos)
+		}
+
+		f.report(vetRefs, f.positions.attr(n, RefAttrName), "%v", msg)
+		return
+	}
+
+	f.refs[name] = n
+}
+
+// comUse records the use of a component by an element
+func (f *vetFile) comUse(n *whtml.Node) {
+	csplit := strings.Split(n.Data, ":")
+	if len(csplit) != 2 {
+		f.used[f.pkg.dir+"."+n.Data] = true
+		return
+	}
+
+	sel, name := csplit[0], csplit[1]
+	f.usedImports[sel] = true
+	imp, ok := f.imports[sel]
+	if !ok {
+		return
+	}
+
+	dir := importDir(filepath.Dir(f.path), imp.importPath)
+	if imp.fuelPkg != nil {
+		dir = imp.fuelPkg.dir
+	}
+
+	f.used[dir+"."+name] = true
+}
+
+// useExpr records the imports used by an expression of the markup
+func (f *vetFile) useExpr(expr string) {
+	x, err := parser.ParseExpr(expr)
+	if err != nil {
+		return
+	}
+
+	ast.Inspect(x, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				f.usedImports[id.Name] = true
+			}
+		}
+
+		return true
+	})
+}
+
+// checkHandler checks that an event attribute of an element is bound to a function
+func (f *vetFile) checkHandler(n *whtml.Node, attr whtml.Attribute) {
+	pos := f.positions.attr(n, attr.Key)
+	switch {
+	case attr.Type == whtml.BoolAttribute:
+		f.report(vetHandlers, pos, "%v has no handler", attr.Key)
+		return
+
+	case attr.Type == whtml.StringAttribute:
+		if len(attr.Mustaches) > 0 {
+			f.report(vetHandlers, pos, "%v is bound to a string, not a function", attr.Key)
+		}
+		return
+	}
+
+	x, err := parser.ParseExpr(attr.Val)
+	if err != nil {
+		return
+	}
+
+	var what string
+	switch x := x.(type) {
+	case *ast.BasicLit:
+		what = "a " + strings.ToLower(x.Kind.String()) + " literal"
+
+	case *ast.CompositeLit:
+		what = "a composite literal"
+
+	case *ast.BinaryExpr:
+		what = "a " + x.Op.String() + " expression"
+
+	case *ast.UnaryExpr:
+		if x.Op != token.ARROW {
+			what = "a " + x.Op.String() + " expression"
+		}
+
+	case *ast.Ident:
+		if x.Name == "true" || x.Name == "false" {
+			what = "a boolean"
+		}
+
+	case *ast.SelectorExpr:
+		if id, ok := x.X.(*ast.Ident); ok && id.Name == "this" {
+			if typ := f.fieldType(x.Sel.Name); typ != nil && typ.notFunc() {
+				what = sfmt("field %v of type %v", x.Sel.Name, types.ExprString(typ.typ))
+			}
+		}
+	}
+
+	if what != "" {
+		f.report(vetHandlers, pos, "%v is bound to %v, not a function", attr.Key, what)
+	}
+}
+
+// fieldType returns the type of a field of the component being checked, nil if it's
+// not a field or the component's struct is unknown
+func (f *vetFile) fieldType(name string) *propSpec {
+	cs, ok := f.pkg.comStructs[f.comName]
+	if !ok {
+		return nil
+	}
+
+	apkg := newAstPkg(f.pkg.pkg, f.pkg.imports)
+	for _, field := range cs.stype.Fields.List {
+		for _, id := range field.Names {
+			if id.Name == name {
+				return &propSpec{typ: field.Type, apkg: apkg}
+			}
+		}
+	}
+
+	props, _ := comProps(f.pkg, f.comName)
+	if prop, ok := props[name]; ok {
+		return &prop
+	}
+
+	return nil
+}
+
+// notFunc tells whether the field is known not to hold a function
+func (p propSpec) notFunc() bool {
+	return p.apkg.notFunc(p.typ, 0)
+}
+
+func (p *astPkg) notFunc(typ ast.Expr, depth int) bool {
+	if depth > 8 {
+		return false
+	}
+
+	switch t := typ.(type) {
+	case *ast.ParenExpr:
+		return p.notFunc(t.X, depth+1)
+
+	case *ast.Ident:
+		if predeclaredTypes[t.Name] {
+			return true
+		}
+
+		obj := t.Obj
+		if obj == nil {
+			obj, _ = pkgLookup(p.pkg.Package, t.Name)
+		}
+
+		if obj != nil {
+			if spec, ok := obj.Decl.(*ast.TypeSpec); ok && spec.Type != nil {
+				return p.notFunc(spec.Type, depth+1)
+			}
+		}
+
+	case *ast.StarExpr, *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.StructType:
+		return true
+	}
+
+	return false
+}
+
+// addRoot records a package under the vetted directory
+func (v *vetter) addRoot(pkg *fuelPkg) {
+	v.roots[pkg.dir] = true
+	if pkg.pkg != nil && pkg.pkg.Name == "main" {
+		v.mains[pkg.dir] = true
+	}
+}
+
+// reportUnused reports the components of the vetted directories that are never used,
+// only for programs: the components of main packages and of the packages they import,
+// the exported components of a library are meant for other modules
+func (v *vetter) reportUnused() {
+	if len(v.mains) == 0 {
+		return
+	}
+
+	for _, def := range v.defs {
+		if v.roots[def.dir] && (v.mains[def.dir] || v.imported[def.dir]) && !v.used[def.key] {
+			f := &vetFile{vetter: v, path: def.file}
+			f.report(vetUnusedCom, def.pos, "component %v is never used", def.name)
+		}
+	}
+}
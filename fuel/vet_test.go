@@ -0,0 +1,190 @@
+package main
+
+import (
+	"go/ast"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gowade/whtml"
+)
+
+func TestVet(t *testing.T) {
+	src := `package app
+
+type Card struct {
+	Title   string
+	Count   int
+	OnClose func()
+}
+
+func (c *Card) close() {}
+
+type Unused struct{}
+
+func (u *Unused) Render() {}
+
+func main() {
+	_ = &Page{}
+}
+`
+
+	nodes := []*whtml.Node{
+		element("import", strAttr("from", "strings"), strAttr("as", "str")),
+		element("import", strAttr("from", "github.com/gowade/wade/components"), strAttr("as", "c"), strAttr("to", "x")),
+		element("import", strAttr("from", "unicode"), strAttr("as", "uc")),
+		appendChildren(element("Page"),
+			appendChildren(element("ul"),
+				appendChildren(element("for", strAttr("v", "item"), exprAttr("range", "this.Items"), strAttr("by", "x")),
+					element("li", exprAttr("key", "item.ID")),
+					element("li"),
+					appendChildren(element("if", exprAttr("cond", "item.Done")), element("span")),
+					element("Card", exprAttr("Title", "str.ToUpper(item.Title)")),
+				),
+			),
+			element("input", strAttr("ref", "name"), exprAttr("onchange", "this.handleChange")),
+			element("input", strAttr("ref", "name")),
+			element("details", boolAttr("open")),
+			element("dialog", exprAttr("open", "this.Shown")),
+			element("c:Link", strAttr("Path", "/")),
+		),
+		appendChildren(element("Card"),
+			element("button", exprAttr("onclick", "this.close")),
+			element("button", exprAttr("onclick", "this.Count")),
+			element("button", exprAttr("onclick", "this.OnClose")),
+			element("button", exprAttr("onclick", `"close"`)),
+			element("button", exprAttr("onclick", "this.Count + 1")),
+			element("button", interpAttr("onclick", "%v", "this.Title")),
+		),
+		element("Unused"),
+	}
+
+	pkg := testPkg(t, "/app", src, map[string]*whtml.Node{"Page": nil, "Card": nil, "Unused": nil})
+	pkg.pkg.Name = "main"
+
+	v := newVetter()
+	v.addRoot(pkg)
+	v.goUses(pkg)
+	f := &vetFile{
+		vetter:  v,
+		pkg:     pkg,
+		path:    "/app/app.whtml",
+		imports: map[string]importedPkg{"c": {importPath: "github.com/gowade/wade/components"}},
+	}
+	f.vetNodes(nodes)
+	v.reportUnused()
+
+	expected := []vetIssue{
+		{Check: vetAttrs, Message: "unknown attribute to of the import tag (accepted: from, as)"},
+		{Check: vetImports, Message: "import uc is not used"},
+		{Check: vetAttrs, Message: "unknown attribute by of the for tag (accepted: k, v, range)"},
+		{Check: vetForKey, Message: "<li> is repeated by a for loop but has no key attribute"},
+		{Check: vetForKey, Message: "<span> is repeated by a for loop but has no key attribute"},
+		{Check: vetRefs, Message: "ref name is declared twice in Page"},
+		{Check: vetHandlers, Message: "onclick is bound to field Count of type int, not a function"},
+		{Check: vetHandlers, Message: "onclick is bound to a string literal, not a function"},
+		{Check: vetHandlers, Message: "onclick is bound to a + expression, not a function"},
+		{Check: vetHandlers, Message: "onclick is bound to a string, not a function"},
+		{Check: vetUnusedCom, Message: "component Unused is never used"},
+	}
+
+	found := map[vetIssue]bool{}
+	for _, issue := range v.issues {
+		found[vetIssue{Check: issue.Check, Message: issue.Message}] = true
+	}
+
+	for _, issue := range expected {
+		if !found[issue] {
+			t.Errorf("expected issue %v: %v", issue.Check, issue.Message)
+		}
+	}
+
+	if len(v.issues) != len(expected) {
+		t.Errorf("expected %v issues, got %v: %v", len(expected), len(v.issues), v.issues)
+	}
+}
+
+func TestVetRootDependency(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "fuelvet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	newPkg := func(name string) *fuelPkg {
+		dir := filepath.Join(tmp, name)
+		path := filepath.Join(dir, name+htmlExt)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		return &fuelPkg{
+			dir:       dir,
+			pkg:       &parsedPkg{Package: &ast.Package{Files: map[string]*ast.File{}}, dir: dir},
+			htmlFiles: []*htmlFile{{path: path}},
+			coms:      comMap{"Card": &htmlFile{}},
+		}
+	}
+
+	vet := func(aName string) []vetIssue {
+		a, b, c := newPkg("a"), newPkg("b"), newPkg("c")
+		a.pkg.Name = aName
+		a.imports = pkgMap{"app/b": b}
+
+		// b is vetted as a dependency of a, before the walk reaches it
+		v := newVetter()
+		for _, pkg := range []*fuelPkg{a, b, c} {
+			v.addRoot(pkg)
+		}
+
+		for _, pkg := range []*fuelPkg{a, b, c} {
+			if err := v.vetPkg(pkg); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, pkg := range []*fuelPkg{b, c} {
+			f := &vetFile{vetter: v, pkg: pkg, path: pkg.htmlFiles[0].path}
+			f.vetNodes([]*whtml.Node{element("Card")})
+		}
+
+		v.reportUnused()
+		return v.issues
+	}
+
+	// c isn't imported by the program, its components may be used elsewhere
+	if issues := vet("main"); len(issues) != 1 || issues[0].Check != vetUnusedCom ||
+		filepath.Base(filepath.Dir(issues[0].File)) != "b" {
+		t.Errorf("expected the unused component of b to be reported, got %v", issues)
+	}
+
+	if issues := vet("a"); len(issues) != 0 {
+		t.Errorf("expected no unused components in a library, got %v", issues)
+	}
+}
+
+func TestEventAttrs(t *testing.T) {
+	for key, expected := range map[string]bool{
+		"onclick": true, "onkeydown": true, "on": false, "open": false, "one-way": false, "onClick": false,
+	} {
+		if isEventAttr(key) != expected {
+			t.Errorf("isEventAttr(%v) should be %v", key, expected)
+		}
+	}
+
+	z := testCompiler(nil)
+	el := element("dialog", exprAttr("open", "this.Shown"), exprAttr("onclose", "this.close"))
+	attrs := z.toTplAttrs(el, el.Attrs)
+	if code := markerRegex.ReplaceAllString(attrs["open"], ""); code != "this.Shown" {
+		t.Errorf("open should not be wrapped as a handler, got %v", code)
+	}
+
+	if code := markerRegex.ReplaceAllString(attrs["onclose"], ""); code != "wade.WrapHandler(this.close)" {
+		t.Errorf("onclose should be wrapped as a handler, got %v", code)
+	}
+}
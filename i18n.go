@@ -0,0 +1,38 @@
+package wade
+
+import (
+	"github.com/gowade/wade/utils/i18n"
+)
+
+func init() {
+	i18n.OnLocaleChange(func(string) {
+		rerenderApp()
+	})
+}
+
+// T translates a message to the active locale, it is used by the code generated
+// for elements with a t attribute
+func T(id string, args ...interface{}) string {
+	return i18n.T(id, args...)
+}
+
+// TN translates a message with plural forms, it is used by the code generated
+// for elements with a t-count attribute
+func TN(id string, count int, args ...interface{}) string {
+	return i18n.TN(id, count, args...)
+}
+
+// SetLocale changes the active locale, the current page is rendered again with the new translations
+func SetLocale(locale string) {
+	i18n.SetLocale(locale)
+}
+
+// rerenderApp renders the current page again, e.g when the locale has changed
+func rerenderApp() {
+	r, ok := app.Router.(*DefaultRouter)
+	if !ok || r.currentComponent == nil || !ClientSide() {
+		return
+	}
+
+	RerenderComponent(r.currentComponent)
+}
@@ -0,0 +1,113 @@
+package wade
+
+import (
+	"github.com/gowade/vdom"
+)
+
+// Outlet is meant to be embedded in layout components, it holds the content
+// rendered by the current child route. A layout's markup shows it with
+// <render content={{ this.OutletContent() }}/>
+type Outlet struct {
+	content vdom.VNode
+}
+
+// OutletContent returns the content rendered by the current child route
+func (o *Outlet) OutletContent() vdom.VNode {
+	return o.content
+}
+
+// SetOutletContent sets the outlet's content, it is called by the router
+func (o *Outlet) SetOutletContent(content vdom.VNode) {
+	o.content = content
+}
+
+// Layout is a component that wraps the content of a group of routes
+type Layout interface {
+	vdom.Component
+	SetOutletContent(content vdom.VNode)
+}
+
+// LayoutFunc creates the layout of a nested route group, it is only called
+// when navigating into the group, the same layout instance is kept
+// while navigating between the group's routes
+type LayoutFunc func(*Context) (Layout, error)
+
+type activeLayout struct {
+	group  *RouteGroup
+	layout Layout
+}
+
+// Nest creates a route group whose routes are rendered inside the layout's outlet
+func (r *DefaultRouter) Nest(prefix string, layout LayoutFunc, middlewares ...Middleware) *RouteGroup {
+	g := r.Group(prefix, middlewares...)
+	g.layout = layout
+	return g
+}
+
+// Nest creates a nested route group whose routes are rendered inside the layout's outlet,
+// the layout itself is rendered inside the outlet of the parent groups' layouts
+func (g *RouteGroup) Nest(prefix string, layout LayoutFunc, middlewares ...Middleware) *RouteGroup {
+	ng := g.Group(prefix, middlewares...)
+	ng.layout = layout
+	return ng
+}
+
+// layoutGroups returns the groups that have a layout, the outermost first
+func (g *RouteGroup) layoutGroups() []*RouteGroup {
+	var groups []*RouteGroup
+	for ; g != nil; g = g.parent {
+		if g.layout != nil {
+			groups = append([]*RouteGroup{g}, groups...)
+		}
+	}
+
+	return groups
+}
+
+// componentNode returns a node that renders the given component instance inside parent
+func componentNode(parent, com vdom.Component) *vdom.VElement {
+	return &vdom.VElement{
+		RenderComponent: func(vdom.Component) *vdom.VElement {
+			return RenderChildComponent(parent, com, nil)
+		},
+	}
+}
+
+// withLayouts puts a route's component inside the layouts of its groups and returns
+// the component to be rendered at the top. Layouts that are already active are reused,
+// so that their state survives the navigation.
+func (c *Context) withLayouts(component vdom.Component) (vdom.Component, error) {
+	var groups []*RouteGroup
+	if c.group != nil {
+		groups = c.group.layoutGroups()
+	}
+
+	active := c.router.layouts
+	layouts := make([]activeLayout, len(groups))
+	for i, g := range groups {
+		if i < len(active) && active[i].group == g {
+			layouts[i] = active[i]
+			continue
+		}
+
+		// the layouts inside a replaced one are replaced as well
+		active = nil
+		layout, err := g.layout(c)
+		if err != nil {
+			return nil, err
+		}
+
+		layouts[i] = activeLayout{
+			group:  g,
+			layout: layout,
+		}
+	}
+
+	c.router.layouts = layouts
+	for i := len(layouts) - 1; i >= 0; i-- {
+		layouts[i].layout.SetOutletContent(componentNode(layouts[i].layout, component))
+		component = layouts[i].layout
+	}
+
+	return component, nil
+}
@@ -0,0 +1,113 @@
+package wade
+
+import (
+	"testing"
+
+	"github.com/gowade/vdom"
+)
+
+// lastMounted is the last component whose BeforeMount hook has been called
+var lastMounted vdom.Component
+
+type testLayout struct {
+	Outlet
+	name string
+}
+
+func (l *testLayout) VDOMRender() *vdom.VElement {
+	return nil
+}
+
+func (l *testLayout) BeforeMount() {
+	lastMounted = l
+}
+
+type testPage struct{}
+
+func (p *testPage) VDOMRender() *vdom.VElement {
+	return nil
+}
+
+func (p *testPage) BeforeMount() {
+	lastMounted = p
+}
+
+// outletComponent renders the content of a layout's outlet and returns its component
+func outletComponent(l Layout) vdom.Component {
+	lastMounted = nil
+	l.(*testLayout).OutletContent().(*vdom.VElement).RenderComponent(nil)
+	return lastMounted
+}
+
+func TestNestedLayouts(t *testing.T) {
+	created := map[string]int{}
+	layoutFunc := func(name string) LayoutFunc {
+		return func(*Context) (Layout, error) {
+			created[name]++
+			return &testLayout{name: name}, nil
+		}
+	}
+
+	r := NewRouter()
+	admin := r.Nest("/admin", layoutFunc("admin"))
+	users := admin.Nest("/users", layoutFunc("users"))
+	plain := admin.Group("/plain")
+	settings := admin.Nest("/settings", layoutFunc("settings"))
+
+	render := func(g *RouteGroup) (vdom.Component, *testPage) {
+		page := &testPage{}
+		top, err := (&Context{router: r, group: g}).withLayouts(page)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return top, page
+	}
+
+	top, _ := render(users)
+	adminLayout, ok := top.(*testLayout)
+	if !ok || adminLayout.name != "admin" || len(r.layouts) != 2 {
+		t.Fatalf("expected the admin layout at the top, got %v", top)
+	}
+
+	usersLayout := r.layouts[1].layout
+	if r.layouts[0].layout != adminLayout || outletComponent(adminLayout) != usersLayout {
+		t.Errorf("expected the users layout inside the admin one, got %+v", r.layouts)
+	}
+
+	// navigating inside the group keeps the layouts
+	top, page := render(users)
+	if top != adminLayout || r.layouts[1].layout != usersLayout || created["admin"] != 1 || created["users"] != 1 {
+		t.Errorf("the layouts should be reused, created %v", created)
+	}
+
+	if outletComponent(usersLayout) != page {
+		t.Errorf("expected the page in the outlet of the users layout")
+	}
+
+	// a group without a layout uses the layouts of its parents
+	_, page = render(plain)
+	if len(r.layouts) != 1 || r.layouts[0].layout != adminLayout || outletComponent(adminLayout) != page {
+		t.Errorf("expected the page in the admin layout, got %+v", r.layouts)
+	}
+
+	render(settings)
+	render(users)
+	if created["admin"] != 1 || created["settings"] != 1 || created["users"] != 2 {
+		t.Errorf("only the inner layouts should be replaced, created %v", created)
+	}
+}
+
+func TestEmptyOutlet(t *testing.T) {
+	// the render tag of a layout shows its fallback content for an empty list
+	var o Outlet
+	if l := NewVNodeList(o.OutletContent()); len(l) != 0 {
+		t.Errorf("expected no nodes for an empty outlet, got %v", l)
+	}
+
+	var el *vdom.VElement
+	o.SetOutletContent(el)
+	if l := NewVNodeList(o.OutletContent(), vdom.VText("a")); len(l) != 1 {
+		t.Errorf("expected a nil element to be left out, got %v", l)
+	}
+}
@@ -0,0 +1,177 @@
+package wade
+
+import (
+	"github.com/gowade/vdom"
+	"github.com/gowade/wade/dom"
+	"github.com/gowade/wade/driver"
+)
+
+// Optional lifecycle interfaces for components, the hooks are called by RenderComponent,
+// RenderChildComponent and RerenderComponent, which the code generated by fuel uses.
+// The DOM hooks are only called in the browser.
+type (
+	// Mounter is implemented by components that need to run code before their first render
+	Mounter interface {
+		BeforeMount()
+	}
+
+	// DidMounter is implemented by components that need to access their DOM node
+	// once it has been inserted into the document
+	DidMounter interface {
+		DidMount(node dom.Node)
+	}
+
+	// WillUpdater is implemented by components that need to run code
+	// before they are rendered again
+	WillUpdater interface {
+		WillUpdate()
+	}
+
+	// DidUpdater is implemented by components that need to access their DOM node
+	// once an update has been applied to it
+	DidUpdater interface {
+		DidUpdate(node dom.Node)
+	}
+
+	// WillUnmounter is implemented by components that need to clean up when they
+	// leave the page, for example to stop a timer
+	WillUnmounter interface {
+		WillUnmount()
+	}
+)
+
+type mountedCom struct {
+	parent vdom.Component
+	node   dom.Node // nil until the component has been rendered to the DOM
+}
+
+// renderPass records the components rendered by a render of the tree under root,
+// root is nil for a render of the whole page
+type renderPass struct {
+	root     vdom.Component
+	rendered map[vdom.Component]bool
+}
+
+var (
+	mountedComs = map[vdom.Component]*mountedCom{}
+	curPass     *renderPass
+)
+
+func init() {
+	driver.BeforePatch = endPass
+}
+
+func beginPass(root vdom.Component) (prev *renderPass) {
+	prev = curPass
+	curPass = &renderPass{
+		root:     root,
+		rendered: map[vdom.Component]bool{},
+	}
+
+	return
+}
+
+// endPass calls the unmount hooks of the components that were under the root
+// of the current pass but haven't been rendered again
+func endPass() {
+	pass := curPass
+	if pass == nil {
+		return
+	}
+
+	curPass = nil
+	var stale []vdom.Component
+	for com := range mountedComs {
+		if !pass.rendered[com] && com != pass.root && isUnder(com, pass.root) {
+			stale = append(stale, com)
+		}
+	}
+
+	for _, com := range stale {
+		m := mountedComs[com]
+		delete(mountedComs, com)
+		if u, ok := com.(WillUnmounter); ok && m.node != nil {
+			u.WillUnmount()
+		}
+	}
+}
+
+func isUnder(com, root vdom.Component) bool {
+	if root == nil {
+		return true
+	}
+
+	for m := mountedComs[com]; m != nil; m = mountedComs[m.parent] {
+		if m.parent == root {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RenderComponent renders a top-level component, calling its lifecycle hooks
+func RenderComponent(com vdom.Component, children []vdom.VNode) *vdom.VElement {
+	return RenderChildComponent(nil, com, children)
+}
+
+// RenderChildComponent renders a component created inside the markup of parent,
+// calling its lifecycle hooks
+func RenderChildComponent(parent, com vdom.Component, children []vdom.VNode) *vdom.VElement {
+	m := mountedComs[com]
+	mounted := m != nil && m.node != nil
+	if mounted {
+		if u, ok := com.(WillUpdater); ok {
+			u.WillUpdate()
+		}
+	} else if mt, ok := com.(Mounter); ok {
+		mt.BeforeMount()
+	}
+
+	vel := vdom.RenderComponent(com, children)
+	if !ClientSide() {
+		return vel
+	}
+
+	if m == nil {
+		m = &mountedCom{}
+		mountedComs[com] = m
+	}
+	m.parent = parent
+
+	if curPass != nil {
+		curPass.rendered[com] = true
+	}
+
+	vel.OnRendered(func(node dom.Node) {
+		m.node = node
+		if mounted {
+			if u, ok := com.(DidUpdater); ok {
+				u.DidUpdate(node)
+			}
+		} else if dm, ok := com.(DidMounter); ok {
+			dm.DidMount(node)
+		}
+	})
+
+	return vel
+}
+
+// RerenderComponent renders a component again after a change of its state,
+// calling the lifecycle hooks of the component and the components inside it
+func RerenderComponent(com vdom.Component) {
+	if u, ok := com.(WillUpdater); ok {
+		u.WillUpdate()
+	}
+
+	prev := beginPass(com)
+	vdom.RerenderComponent(com)
+	endPass()
+	curPass = prev
+
+	if m := mountedComs[com]; m != nil && m.node != nil {
+		if u, ok := com.(DidUpdater); ok {
+			u.DidUpdate(m.node)
+		}
+	}
+}
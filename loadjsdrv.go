@@ -1,4 +1,6 @@
+//go:build js
 // +build js
+
 package wade
 
 import _ "github.com/gowade/wade/driver/jsdrv"
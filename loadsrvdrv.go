@@ -0,0 +1,6 @@
+//go:build !js
+// +build !js
+
+package wade
+
+import _ "github.com/gowade/wade/driver/srvdrv"
@@ -0,0 +1,80 @@
+package wade
+
+import (
+	"fmt"
+	"path"
+)
+
+// Middleware wraps a controller function. It can run code before and after the
+// controller, or short-circuit it by returning an error or a Redirect
+// without calling the wrapped controller.
+type Middleware func(ControllerFunc) ControllerFunc
+
+// Redirect is an error returned by a controller or a middleware
+// to navigate to another route instead of rendering the current one
+type Redirect struct {
+	RouteName string
+	Params    []interface{}
+}
+
+func (r *Redirect) Error() string {
+	return fmt.Sprintf(`redirect to route "%v"`, r.RouteName)
+}
+
+// RedirectTo returns a Redirect error for the given route
+func RedirectTo(routeName string, params ...interface{}) error {
+	return &Redirect{
+		RouteName: routeName,
+		Params:    params,
+	}
+}
+
+func chainMiddlewares(handler ControllerFunc, middlewares []Middleware) ControllerFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+
+	return handler
+}
+
+// RouteGroup registers routes under a common path prefix, with middlewares
+// that only apply to those routes
+type RouteGroup struct {
+	router      *DefaultRouter
+	parent      *RouteGroup
+	prefix      string
+	middlewares []Middleware
+	layout      LayoutFunc
+}
+
+// Use adds middlewares for the group's routes,
+// they run after the router's and the parent groups' middlewares
+func (g *RouteGroup) Use(middlewares ...Middleware) {
+	g.middlewares = append(g.middlewares, middlewares...)
+}
+
+// Group creates a nested route group
+func (g *RouteGroup) Group(prefix string, middlewares ...Middleware) *RouteGroup {
+	return &RouteGroup{
+		router:      g.router,
+		parent:      g,
+		prefix:      path.Join(g.prefix, prefix),
+		middlewares: middlewares,
+	}
+}
+
+func (g *RouteGroup) allMiddlewares() []Middleware {
+	if g.parent == nil {
+		return g.middlewares
+	}
+
+	return append(append([]Middleware{}, g.parent.allMiddlewares()...), g.middlewares...)
+}
+
+// Handle registers a handler for the given route, prefixed with the group's prefix
+func (g *RouteGroup) Handle(route string, routeName string, handler ControllerFunc) {
+	g.router.Handle(path.Join(g.prefix, route), routeName, func(ctx *Context) error {
+		ctx.group = g
+		return chainMiddlewares(handler, g.allMiddlewares())(ctx)
+	})
+}
@@ -2,6 +2,7 @@ package wade
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	gourl "net/url"
 	"path"
@@ -20,6 +21,7 @@ type (
 		*defaultRouter
 		nameMap      map[string]string
 		errorHandler func(error)
+		middlewares  []Middleware
 	}
 
 	defaultRouter struct {
@@ -27,6 +29,8 @@ type (
 		routes           []urlrouter.Record
 		notFoundHandler  ControllerFunc
 		currentComponent vdom.Component
+		layouts          []activeLayout
+		cancelNav        context.CancelFunc
 	}
 )
 
@@ -54,13 +58,28 @@ func (r *DefaultRouter) Handle(route string, routeName string, handler Controlle
 	}
 
 	if r.nameMap[routeName] != "" {
-		panic(fmt.Errorf(`routeName "%v" is already taken`))
+		panic(fmt.Errorf(`routeName "%v" is already taken`, routeName))
 	}
 
 	r.defaultRouter.handle(route, handler)
 	r.nameMap[routeName] = route
 }
 
+// Use adds middlewares that run for every route, including the not found handler.
+// Middlewares run in the order they are added, before the route groups' middlewares.
+func (r *DefaultRouter) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Group creates a route group with the given path prefix and middlewares
+func (r *DefaultRouter) Group(prefix string, middlewares ...Middleware) *RouteGroup {
+	return &RouteGroup{
+		router:      r,
+		prefix:      path.Join("/", prefix),
+		middlewares: middlewares,
+	}
+}
+
 func (r *DefaultRouter) SetNotFoundHandler(c ControllerFunc) {
 	r.defaultRouter.setNotFoundHandler(c)
 }
@@ -125,15 +144,32 @@ func (r *DefaultRouter) Render(url *gourl.URL) {
 		handler = r.notFoundHandler
 	}
 
-	cf := handler.(ControllerFunc)
+	cf := chainMiddlewares(handler.(ControllerFunc), r.middlewares)
+
+	// the previous navigation is over, its pending requests are not needed anymore
+	if r.cancelNav != nil {
+		r.cancelNav()
+	}
+
+	navCtx, cancel := context.WithCancel(context.Background())
+	r.cancelNav = cancel
 
 	ctx := &Context{
-		router: r,
-		URL:    url,
-		Params: params,
+		Context: navCtx,
+		router:  r,
+		URL:     url,
+		Params:  params,
 	}
 	err := cf(ctx)
 
+	if redirect, ok := err.(*Redirect); ok {
+		err = ctx.GoToRoute(redirect.RouteName, redirect.Params...)
+	}
+
+	if err == context.Canceled {
+		err = nil
+	}
+
 	if err != nil {
 		if r.errorHandler == nil {
 			panic(err)
@@ -0,0 +1,36 @@
+package wade
+
+import (
+	"github.com/gowade/vdom"
+)
+
+// SlotTag is the tag of the elements that carry the content of a named slot to a component,
+// they are created by the code generated for <slot:name> tags and are never rendered
+const SlotTag = "wade:slot"
+
+// SlotFill returns the element that passes the content of a named slot to a component
+func SlotFill(name string, nodes []vdom.VNode) *vdom.VElement {
+	return &vdom.VElement{
+		Tag:      SlotTag,
+		Key:      name,
+		Children: nodes,
+	}
+}
+
+// SlotContent returns the content given for a slot among the children of a component,
+// the default slot, named "", gets the children that don't fill a named slot
+func SlotContent(children []vdom.VNode, name string) []vdom.VNode {
+	var l []vdom.VNode
+	for _, c := range children {
+		el, ok := c.(*vdom.VElement)
+		isFill := ok && el.Tag == SlotTag
+		switch {
+		case name == "" && !isFill:
+			l = append(l, c)
+		case name != "" && isFill && el.Key == name:
+			l = append(l, el.Children...)
+		}
+	}
+
+	return l
+}
@@ -0,0 +1,113 @@
+// Package conv converts strings to Go values with reflection,
+// it's shared by wade's route binding and the form package
+package conv
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// IsList tells whether a value of type t is set from a list of strings
+// rather than a single one, that is whether it's a slice that isn't
+// an encoding.TextUnmarshaler
+func IsList(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && !reflect.PtrTo(t).Implements(textUnmarshalerType)
+}
+
+// SetString parses s into v. Supported types are strings, bools, numbers, time.Time
+// (RFC 3339 unless a layout is given), time.Duration, types implementing
+// encoding.TextUnmarshaler and pointers to those.
+func SetString(v reflect.Value, s string, layout string) error {
+	if v.Kind() == reflect.Ptr {
+		ptr := reflect.New(v.Type().Elem())
+		if err := SetString(ptr.Elem(), s, layout); err != nil {
+			return err
+		}
+
+		v.Set(ptr)
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) && v.Type() != timeType {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch v.Type() {
+	case timeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+
+		t, err := time.Parse(layout, strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("must be a time with layout %v", layout)
+		}
+
+		v.Set(reflect.ValueOf(t))
+		return nil
+
+	case durationType:
+		d, err := time.ParseDuration(strings.TrimSpace(s))
+		if err != nil {
+			return fmt.Errorf("must be a duration")
+		}
+
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+
+	case reflect.Bool:
+		// "on" is the value of a checked checkbox without a value attribute
+		s = strings.TrimSpace(s)
+		b, err := strconv.ParseBool(s)
+		switch {
+		case s == "on":
+			b = true
+		case s == "off":
+			b = false
+		case err != nil:
+			return fmt.Errorf("must be a boolean")
+		}
+		v.SetBool(b)
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be an integer")
+		}
+		v.SetInt(n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a positive integer")
+		}
+		v.SetUint(n)
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("must be a number")
+		}
+		v.SetFloat(f)
+
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package conv
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type upper string
+
+func (u *upper) UnmarshalText(text []byte) error {
+	*u = upper("<" + string(text) + ">")
+	return nil
+}
+
+func TestSetString(t *testing.T) {
+	var s struct {
+		S   string
+		B   bool
+		I   int8
+		U   uint
+		F   float64
+		T   time.Time
+		D   time.Duration
+		P   *int
+		Txt upper
+		M   map[string]string
+	}
+
+	v := reflect.ValueOf(&s).Elem()
+	cases := []struct {
+		field, s, layout, err string
+	}{
+		{"S", "hello", "", ""},
+		{"B", "off", "", ""},
+		{"B", "on", "", ""},
+		{"B", "yes", "", "must be a boolean"},
+		{"I", " 12 ", "", ""},
+		{"I", "300", "", "must be an integer"},
+		{"U", "-1", "", "must be a positive integer"},
+		{"F", "1.5", "", ""},
+		{"T", "02/04/2016", "02/01/2006", ""},
+		{"T", "2016", "", "must be a time with layout " + time.RFC3339},
+		{"D", "1m", "", ""},
+		{"P", "3", "", ""},
+		{"Txt", "a", "", ""},
+		{"M", "a", "", "unsupported type map[string]string"},
+	}
+
+	for _, c := range cases {
+		err := SetString(v.FieldByName(c.field), c.s, c.layout)
+		switch {
+		case c.err == "" && err != nil:
+			t.Errorf("%v: unexpected error %v", c.field, err)
+		case c.err != "" && (err == nil || err.Error() != c.err):
+			t.Errorf("%v: expected error %q, got %v", c.field, c.err, err)
+		}
+	}
+
+	if s.S != "hello" || !s.B || s.I != 12 || s.F != 1.5 || s.T.Month() != time.April ||
+		s.D != time.Minute || s.P == nil || *s.P != 3 || s.Txt != "<a>" {
+		t.Errorf("unexpected values %+v", s)
+	}
+
+	if !IsList(reflect.TypeOf([]int{})) || IsList(reflect.TypeOf(ip{})) {
+		t.Errorf("IsList: slices that unmarshal text should be set from a single string")
+	}
+}
+
+type ip []byte
+
+func (n *ip) UnmarshalText(text []byte) error {
+	*n = text
+	return nil
+}
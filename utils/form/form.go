@@ -0,0 +1,239 @@
+// Package form fills Go structs from the named fields of HTML forms and validates them
+// with rules written in struct tags. It only uses the dom package's interfaces,
+// so it works the same way in the browser and with an in-memory DOM.
+//
+//	type Signup struct {
+//		Email    string `form:"email" validate:"required" pattern:"^[^@]+@[^@]+$"`
+//		Password string `form:"password" validate:"required,min=8"`
+//		Age      int    `form:"age" validate:"min=13,max=130"`
+//		Terms    bool   `form:"terms" validate:"required"`
+//		Username string `form:"username" validate:"required,available"`
+//	}
+//
+//	var s Signup
+//	if errs := form.Bind(this.Refs().signupForm, &s); errs != nil {
+//		this.setErrors(errs) // <p class="error">{{ this.Errors.Get("email") }}</p>
+//		return
+//	}
+package form
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gowade/wade/dom"
+	"github.com/gowade/wade/utils/conv"
+)
+
+const (
+	nameTag     = "form"
+	validateTag = "validate"
+	patternTag  = "pattern"
+	layoutTag   = "layout"
+)
+
+// FieldError is an error for a single field of a form
+type FieldError struct {
+	Field string // name of the struct field
+	Name  string // name of the form field
+	Rule  string // the rule that failed, "type" if the value could not be converted
+	Msg   string
+}
+
+func (e *FieldError) Error() string {
+	return e.Name + ": " + e.Msg
+}
+
+// Errors is the list of the errors of a form's fields, in the order of the struct's fields
+type Errors []*FieldError
+
+func (errs Errors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Get returns the message of the first error for the field with the given form name
+// or struct field name, an empty string if there's none
+func (errs Errors) Get(name string) string {
+	if e := errs.Field(name); e != nil {
+		return e.Msg
+	}
+
+	return ""
+}
+
+// Has tells whether there's an error for the given field
+func (errs Errors) Has(name string) bool {
+	return errs.Field(name) != nil
+}
+
+// Field returns the first error for the field with the given form name or struct field name
+func (errs Errors) Field(name string) *FieldError {
+	for _, e := range errs {
+		if e.Name == name || e.Field == name {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Values returns the values of a form's named fields, unchecked checkboxes and radio buttons
+// are left out, like in a browser's form submission
+func Values(form dom.FormEl) map[string][]string {
+	values := make(map[string][]string)
+	for _, input := range form.Fields() {
+		name := input.Name()
+		if name == "" {
+			continue
+		}
+
+		switch input.InputType() {
+		case "checkbox", "radio":
+			if !input.Checked() {
+				continue
+			}
+		case "submit", "button", "reset", "image", "file":
+			continue
+		}
+
+		values[name] = append(values[name], input.Value())
+	}
+
+	return values
+}
+
+// Bind fills the struct pointed to by dest from the form's fields and validates it,
+// it returns nil if there's no error
+func Bind(form dom.FormEl, dest interface{}) Errors {
+	if errs := Decode(form, dest); errs != nil {
+		return errs
+	}
+
+	return Validate(dest)
+}
+
+// Decode fills the struct pointed to by dest from the form's fields. The form field of a struct
+// field is given by its form tag, or is the struct field's name. Fields tagged with form:"-"
+// and fields without a form field are skipped. A checked checkbox without a value attribute
+// gives "on", which sets a bool to true, an unchecked one sets it to false.
+// Field types are the ones of conv.SetString and slices of those, times are parsed with
+// the layout of a layout tag.
+func Decode(form dom.FormEl, dest interface{}) Errors {
+	names := make(map[string]bool)
+	for _, input := range form.Fields() {
+		names[input.Name()] = true
+	}
+
+	return decode(Values(form), names, dest)
+}
+
+// DecodeValues is like Decode, with the values of a form,
+// the fields without a value are skipped
+func DecodeValues(values map[string][]string, dest interface{}) Errors {
+	names := make(map[string]bool, len(values))
+	for name := range values {
+		names[name] = true
+	}
+
+	return decode(values, names, dest)
+}
+
+// decode sets the struct fields whose form field is in names
+func decode(values map[string][]string, names map[string]bool, dest interface{}) Errors {
+	v := structValue("Decode", dest)
+	var errs Errors
+	eachField(v, func(field reflect.StructField, fv reflect.Value, name string) {
+		if !names[name] {
+			return
+		}
+
+		vals := values[name]
+		if fv.Kind() == reflect.Bool {
+			// unchecked checkboxes are not in the values
+			fv.SetBool(len(vals) > 0 && vals[0] != "" && vals[0] != "off" && vals[0] != "false")
+			return
+		}
+
+		var err error
+		layout := field.Tag.Get(layoutTag)
+		if conv.IsList(fv.Type()) {
+			sl := reflect.MakeSlice(fv.Type(), 0, len(vals))
+			for _, s := range vals {
+				if s == "" {
+					continue
+				}
+
+				ev := reflect.New(fv.Type().Elem()).Elem()
+				if err = conv.SetString(ev, s, layout); err != nil {
+					break
+				}
+				sl = reflect.Append(sl, ev)
+			}
+
+			if err == nil {
+				fv.Set(sl)
+			}
+		} else if len(vals) == 0 || vals[0] == "" {
+			fv.Set(reflect.Zero(fv.Type()))
+		} else {
+			err = conv.SetString(fv, vals[0], layout)
+		}
+
+		if err != nil {
+			errs = append(errs, &FieldError{
+				Field: field.Name,
+				Name:  name,
+				Rule:  "type",
+				Msg:   err.Error(),
+			})
+		}
+	})
+
+	return errs
+}
+
+func structValue(funcName string, dest interface{}) reflect.Value {
+	v := reflect.ValueOf(dest)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		panic(fmt.Errorf("%v: expected a pointer to a struct, got %T", funcName, dest))
+	}
+
+	return v
+}
+
+// eachField calls fn for the exported fields of a struct and the fields of its embedded structs
+func eachField(v reflect.Value, fn func(field reflect.StructField, fv reflect.Value, name string)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			eachField(v.Field(i), fn)
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		if tag := field.Tag.Get(nameTag); tag != "" {
+			if tag == "-" {
+				continue
+			}
+
+			name = tag
+		}
+
+		fn(field, v.Field(i), name)
+	}
+}
@@ -0,0 +1,183 @@
+package form
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gowade/wade/dom"
+	"github.com/gowade/wade/dom/memdom"
+)
+
+type signup struct {
+	Email    string   `form:"email" validate:"required" pattern:"[^@]+@[^@]+"`
+	Password string   `form:"password" validate:"required,min=8"`
+	Age      int      `form:"age" validate:"min=13,max=130"`
+	Terms    bool     `form:"terms" validate:"required"`
+	Topics   []string `form:"topic"`
+	Username string   `form:"username" validate:"lowercase"`
+	Country  string   `form:"country"`
+}
+
+func input(form *memdom.Node, inputType, name, value string) *memdom.Node {
+	el := form.AppendChild(memdom.NewElement("input"))
+	el.SetAttr("type", inputType)
+	el.SetAttr("name", name)
+	if value != "" {
+		el.SetAttr("value", value)
+	}
+
+	return el
+}
+
+func testForm() (dom.FormEl, map[string]*memdom.Node) {
+	form := memdom.NewElement("form")
+	els := map[string]*memdom.Node{
+		"email":    input(form, "email", "email", "a@b.c"),
+		"password": input(form, "password", "password", "secret"),
+		"age":      input(form, "number", "age", "x"),
+		"terms":    input(form, "checkbox", "terms", ""),
+		"topic1":   input(form, "checkbox", "topic", "go"),
+		"topic2":   input(form, "checkbox", "topic", "js"),
+		"username": input(form, "text", "username", "Bob"),
+	}
+
+	input(form, "submit", "", "Sign up")
+	els["topic1"].SetAttr("checked", true)
+
+	sel := form.AppendChild(memdom.NewElement("select"))
+	sel.SetAttr("name", "country")
+	for _, c := range []string{"fr", "vn"} {
+		opt := sel.AppendChild(memdom.NewElement("option"))
+		opt.SetAttr("value", c)
+		if c == "vn" {
+			opt.SetAttr("selected", true)
+		}
+	}
+
+	return dom.CreateNode(form).(dom.FormEl), els
+}
+
+func TestBind(t *testing.T) {
+	RegisterRule("lowercase", func(v interface{}) string {
+		if s := v.(string); s != strings.ToLower(s) {
+			return "must be in lower case"
+		}
+
+		return ""
+	})
+
+	form, els := testForm()
+	var s signup
+	errs := Bind(form, &s)
+	if len(errs) != 1 || errs.Get("age") != "must be an integer" {
+		t.Fatalf("expected a conversion error for age, got %v", errs)
+	}
+
+	els["age"].SetProp("value", "10")
+	errs = Bind(form, &s)
+	expected := map[string]string{
+		"password": "must have at least 8 characters",
+		"age":      "must be at least 13",
+		"terms":    "is required",
+		"Username": "must be in lower case",
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %v errors, got %v", len(expected), errs)
+	}
+
+	for name, msg := range expected {
+		if errs.Get(name) != msg {
+			t.Errorf("%v: expected error %q, got %q", name, msg, errs.Get(name))
+		}
+	}
+
+	if s.Country != "vn" || len(s.Topics) != 1 || s.Topics[0] != "go" {
+		t.Errorf("unexpected decoded values %+v", s)
+	}
+
+	els["password"].SetProp("value", "secret123")
+	els["age"].SetProp("value", "20")
+	els["terms"].SetProp("checked", true)
+	els["username"].SetProp("value", "bob")
+	els["email"].SetProp("value", "nope")
+	errs = Bind(form, &s)
+	if len(errs) != 1 || errs[0].Rule != "pattern" || errs[0].Field != "Email" {
+		t.Fatalf("expected a pattern error for the email, got %v", errs)
+	}
+
+	els["email"].SetProp("value", "bob@example.com")
+	if errs := Bind(form, &s); errs != nil {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+
+	if !s.Terms || s.Age != 20 {
+		t.Errorf("unexpected decoded values %+v", s)
+	}
+}
+
+func TestDecodeValues(t *testing.T) {
+	type event struct {
+		Title  string    `form:"title"`
+		Notes  string    `form:"notes"`
+		Day    time.Time `form:"day" layout:"2006-01-02"`
+		Seats  *int      `form:"seats"`
+		Guests []struct {
+			Name string
+		} `form:"guest"`
+	}
+
+	e := event{Title: "Party", Notes: "bring food"}
+	errs := DecodeValues(map[string][]string{
+		"day":   {"2016-04-02"},
+		"seats": {"12"},
+		"guest": {"bob"},
+	}, &e)
+
+	if len(errs) != 1 || errs[0].Field != "Guests" || errs[0].Rule != "type" ||
+		!strings.HasPrefix(errs[0].Error(), "guest: unsupported type") {
+		t.Errorf("expected a type error for the guests, got %v", errs)
+	}
+
+	if e.Title != "Party" || e.Notes != "bring food" {
+		t.Errorf("the fields without a value should be left as they are, got %+v", e)
+	}
+
+	if e.Day.Format("2006-01-02") != "2016-04-02" || e.Seats == nil || *e.Seats != 12 {
+		t.Errorf("unexpected decoded values %+v", e)
+	}
+}
+
+func TestValidateBounds(t *testing.T) {
+	type order struct {
+		Note  string            `validate:"max=3"`
+		Items []string          `validate:"min=2"`
+		Tags  map[string]string `validate:"max=1"`
+		Count int               `validate:"max=5"`
+	}
+
+	errs := Validate(&order{
+		Note:  "wrapped",
+		Items: []string{"a"},
+		Tags:  map[string]string{"a": "1", "b": "2"},
+		Count: 6,
+	})
+
+	expected := map[string]string{
+		"Note":  "must have at most 3 characters",
+		"Items": "must have at least 2 items",
+		"Tags":  "must have at most 1 items",
+		"Count": "must be at most 5",
+	}
+
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %v errors, got %v", len(expected), errs)
+	}
+
+	for name, msg := range expected {
+		if errs.Get(name) != msg {
+			t.Errorf("%v: expected error %q, got %q", name, msg, errs.Get(name))
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package form
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RuleFunc is a custom validation rule, it returns an error message
+// for an invalid value, or an empty string
+type RuleFunc func(value interface{}) string
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[string]RuleFunc{}
+
+	patternsMu sync.Mutex
+	patterns   = map[string]*regexp.Regexp{}
+)
+
+// RegisterRule registers a custom validation rule to be used in validate tags
+func RegisterRule(name string, fn RuleFunc) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+
+	switch name {
+	case "required", "min", "max":
+		panic(fmt.Errorf("form: %v is a built-in rule", name))
+	}
+
+	rules[name] = fn
+}
+
+func customRule(name string) RuleFunc {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	return rules[name]
+}
+
+func compilePattern(pattern string) *regexp.Regexp {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+
+	re, ok := patterns[pattern]
+	if !ok {
+		re = regexp.MustCompile("^(?:" + pattern + ")$")
+		patterns[pattern] = re
+	}
+
+	return re
+}
+
+// Validate checks the fields of a struct against the rules of their tags:
+//
+//	validate:"required"      the value must not be the zero value, a bool must be true
+//	validate:"min=3,max=10"  bounds for numbers, length bounds for strings and slices
+//	validate:"name"          a rule registered with RegisterRule
+//	pattern:"[a-z]+"         the whole string must match the regular expression
+//
+// Empty values that are not required are not checked against the other rules.
+// It returns nil if there's no error.
+func Validate(v interface{}) Errors {
+	var errs Errors
+	eachField(structValue("Validate", v), func(field reflect.StructField, fv reflect.Value, name string) {
+		if msg, rule := validateField(field, fv); msg != "" {
+			errs = append(errs, &FieldError{
+				Field: field.Name,
+				Name:  name,
+				Rule:  rule,
+				Msg:   msg,
+			})
+		}
+	})
+
+	return errs
+}
+
+// validateField returns the error message and the rule for the first rule the value breaks
+func validateField(field reflect.StructField, v reflect.Value) (msg, rule string) {
+	var ruleList []string
+	if tag := field.Tag.Get(validateTag); tag != "" {
+		ruleList = strings.Split(tag, ",")
+	}
+
+	empty := isEmpty(v)
+	for _, r := range ruleList {
+		if strings.TrimSpace(r) == "required" && empty {
+			return "is required", "required"
+		}
+	}
+
+	if empty {
+		return "", ""
+	}
+
+	if pattern, ok := field.Tag.Lookup(patternTag); ok && v.Kind() == reflect.String {
+		if !compilePattern(pattern).MatchString(v.String()) {
+			return "has an invalid format", "pattern"
+		}
+	}
+
+	for _, r := range ruleList {
+		r = strings.TrimSpace(r)
+		name, arg := r, ""
+		if i := strings.IndexByte(r, '='); i != -1 {
+			name, arg = r[:i], r[i+1:]
+		}
+
+		switch name {
+		case "", "required":
+			continue
+
+		case "min", "max":
+			if msg := checkBound(v, name, arg); msg != "" {
+				return msg, name
+			}
+
+		default:
+			fn := customRule(name)
+			if fn == nil {
+				panic(fmt.Errorf("form: unknown validation rule %v for field %v", name, field.Name))
+			}
+
+			if msg := fn(v.Interface()); msg != "" {
+				return msg, name
+			}
+		}
+	}
+
+	return "", ""
+}
+
+func isEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
+
+func checkBound(v reflect.Value, rule, arg string) string {
+	bound, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		panic(fmt.Errorf("form: invalid %v rule argument %q", rule, arg))
+	}
+
+	var n float64
+	var unit string
+	switch v.Kind() {
+	case reflect.String:
+		n, unit = float64(len([]rune(v.String()))), "characters"
+	case reflect.Slice, reflect.Map:
+		n, unit = float64(v.Len()), "items"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return ""
+	}
+
+	switch {
+	case rule == "min" && n < bound && unit != "":
+		return fmt.Sprintf("must have at least %v %v", arg, unit)
+	case rule == "min" && n < bound:
+		return fmt.Sprintf("must be at least %v", arg)
+	case rule == "max" && n > bound && unit != "":
+		return fmt.Sprintf("must have at most %v %v", arg, unit)
+	case rule == "max" && n > bound:
+		return fmt.Sprintf("must be at most %v", arg)
+	}
+
+	return ""
+}
@@ -0,0 +1,285 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	gourl "net/url"
+	"strings"
+	"time"
+)
+
+type (
+	// RequestInterceptor is called before a request is sent, e.g to add an auth header.
+	// Returning an error aborts the request.
+	RequestInterceptor func(*Request) error
+
+	// ResponseInterceptor is called with the result of every attempt of a request,
+	// resp is nil if err is not. It returns the response and error passed on to the caller,
+	// or to the next interceptor.
+	ResponseInterceptor func(req *Request, resp *Response, err error) (*Response, error)
+
+	// Backoff returns the delay before the given retry, attempt starts at 1
+	Backoff func(attempt int) time.Duration
+)
+
+// StatusError is returned by the Client for responses with a non-2xx status code
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Response   *Response
+}
+
+func (e *StatusError) Error() string {
+	status := e.Status
+	if status == "" {
+		status = fmt.Sprint(e.StatusCode)
+	}
+
+	return fmt.Sprintf("%v %v: %v", e.Method, e.URL, status)
+}
+
+// ExponentialBackoff returns a Backoff that doubles the delay on each retry,
+// starting with base, up to max
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+
+		if d > max {
+			d = max
+		}
+
+		return d
+	}
+}
+
+// Client sends JSON requests through a Driver, the package's driver if Driver is nil
+type Client struct {
+	Driver  Driver
+	BaseURL string
+	Header  Header
+	Timeout time.Duration
+
+	// MaxRetries is the number of times an idempotent request is retried after
+	// a network error or a 5xx response
+	MaxRetries int
+	Backoff    Backoff
+
+	requestInterceptors  []RequestInterceptor
+	responseInterceptors []ResponseInterceptor
+}
+
+// NewClient returns a client that uses the package's driver
+func NewClient(baseURL string) *Client {
+	return &Client{
+		BaseURL: baseURL,
+		Header:  make(Header),
+		Backoff: ExponentialBackoff(200*time.Millisecond, 5*time.Second),
+	}
+}
+
+// OnRequest adds request interceptors, they are called in the order they are added
+func (c *Client) OnRequest(interceptors ...RequestInterceptor) {
+	c.requestInterceptors = append(c.requestInterceptors, interceptors...)
+}
+
+// OnResponse adds response interceptors, they are called in the order they are added
+func (c *Client) OnResponse(interceptors ...ResponseInterceptor) {
+	c.responseInterceptors = append(c.responseInterceptors, interceptors...)
+}
+
+func (c *Client) driver() Driver {
+	if c.Driver != nil {
+		return c.Driver
+	}
+
+	return driver
+}
+
+func isIdempotent(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+
+	return false
+}
+
+// NewRequest creates a request to a path relative to the client's base URL, with the client's
+// headers and timeout. body is encoded to JSON, unless it is nil or a []byte.
+func (c *Client) NewRequest(method, path string, body interface{}) (*Request, error) {
+	url := path
+	if c.BaseURL != "" && !strings.Contains(path, "://") {
+		url = strings.TrimSuffix(c.BaseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+	}
+
+	var data []byte
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		data = b
+	default:
+		var err error
+		data, err = json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := NewRequest(method, url, data)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, values := range c.Header {
+		req.Header[k] = append([]string{}, values...)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		if _, raw := body.([]byte); !raw {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	req.Timeout = c.Timeout
+	return req, nil
+}
+
+// Do sends a request, retrying it if it is idempotent. Responses with a non-2xx
+// status code are returned with a *StatusError. The request and the retries are
+// given up when ctx is done.
+func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
+	req = req.WithContext(ctx)
+	for _, intercept := range c.requestInterceptors {
+		if err := intercept(req); err != nil {
+			return nil, err
+		}
+	}
+
+	retries := 0
+	if isIdempotent(req.Method) {
+		retries = c.MaxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.driver().Do(req)
+		if err == nil && resp == nil {
+			err = noResponseError(req)
+		}
+
+		if err == nil && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+			err = &StatusError{
+				Method:     req.Method,
+				URL:        req.URL.String(),
+				StatusCode: resp.StatusCode,
+				Status:     resp.Status,
+				Response:   resp,
+			}
+		}
+
+		for _, intercept := range c.responseInterceptors {
+			resp, err = intercept(req, resp, err)
+		}
+
+		// an interceptor may have dropped both the response and the error
+		if err == nil && resp == nil {
+			err = noResponseError(req)
+		}
+
+		if err == nil || attempt >= retries || !retryable(ctx, err) {
+			return resp, err
+		}
+
+		if c.Backoff != nil {
+			select {
+			case <-time.After(c.Backoff(attempt + 1)):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+}
+
+func noResponseError(req *Request) error {
+	return fmt.Errorf("%v %v: no response", req.Method, req.URL)
+}
+
+// retryable tells whether a failed request may succeed if sent again
+func retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if serr, ok := err.(*StatusError); ok {
+		return serr.StatusCode >= 500
+	}
+
+	return true
+}
+
+// DoJSON sends a request and decodes the JSON response body into result, if it is not nil
+func (c *Client) DoJSON(ctx context.Context, req *Request, result interface{}) error {
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	if result == nil || len(resp.Body) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(resp.Body, result); err != nil {
+		return fmt.Errorf("%v %v: invalid JSON response: %v", req.Method, req.URL, err)
+	}
+
+	return nil
+}
+
+func (c *Client) send(ctx context.Context, method, path string, body, result interface{}) error {
+	req, err := c.NewRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+
+	return c.DoJSON(ctx, req, result)
+}
+
+// Get sends a GET request and decodes the JSON response into result,
+// query values are added to the URL
+func (c *Client) Get(ctx context.Context, path string, query gourl.Values, result interface{}) error {
+	if len(query) > 0 {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+
+		path += sep + query.Encode()
+	}
+
+	return c.send(ctx, "GET", path, nil, result)
+}
+
+// Post sends body encoded to JSON in a POST request and decodes the JSON response into result
+func (c *Client) Post(ctx context.Context, path string, body, result interface{}) error {
+	return c.send(ctx, "POST", path, body, result)
+}
+
+// Put sends body encoded to JSON in a PUT request and decodes the JSON response into result
+func (c *Client) Put(ctx context.Context, path string, body, result interface{}) error {
+	return c.send(ctx, "PUT", path, body, result)
+}
+
+// Delete sends a DELETE request and decodes the JSON response into result
+func (c *Client) Delete(ctx context.Context, path string, result interface{}) error {
+	return c.send(ctx, "DELETE", path, nil, result)
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type seqBackend struct {
+	responses []testResponse
+	requests  []*Request
+}
+
+func (sb *seqBackend) Do(r *Request) (*Response, error) {
+	sb.requests = append(sb.requests, r)
+	resp := sb.responses[0]
+	if len(sb.responses) > 1 {
+		sb.responses = sb.responses[1:]
+	}
+
+	return &Response{
+		Body:       []byte(resp.Body),
+		StatusCode: resp.StatusCode,
+	}, nil
+}
+
+func TestClientJSON(t *testing.T) {
+	ctx := context.Background()
+	sb := &stubBackend{}
+	c := NewClient("http://api.test/v1/")
+	c.Driver = sb
+	c.OnRequest(func(r *Request) error {
+		r.Header.Set("Authorization", "Bearer x")
+		return nil
+	})
+
+	sb.Response(200, `{"id": 3, "title": "ABC"}`)
+	var project struct {
+		ID    int
+		Title string
+	}
+	if err := c.Post(ctx, "/projects", map[string]string{"title": "ABC"}, &project); err != nil {
+		t.Fatal(err)
+	}
+
+	if project.ID != 3 || project.Title != "ABC" {
+		t.Errorf("unexpected result %+v", project)
+	}
+
+	sb.Response(404, `{}`)
+	err := c.Get(ctx, "projects/4", nil, &project)
+	if serr, ok := err.(*StatusError); !ok || serr.StatusCode != 404 {
+		t.Fatalf("expected a 404 StatusError, got %v", err)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	ctx := context.Background()
+	sb := &seqBackend{responses: []testResponse{{500, ""}, {503, ""}, {200, `[1]`}}}
+	c := NewClient("")
+	c.Driver = sb
+	c.MaxRetries = 2
+	c.Backoff = func(int) time.Duration { return 0 }
+
+	var calls int
+	c.OnResponse(func(req *Request, resp *Response, err error) (*Response, error) {
+		calls++
+		return resp, err
+	})
+
+	var result []int
+	if err := c.Get(ctx, "/x", nil, &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sb.requests) != 3 || calls != 3 || len(result) != 1 {
+		t.Errorf("expected 3 attempts, got %v requests and %v intercepted responses", len(sb.requests), calls)
+	}
+
+	// POST is not idempotent, it is not retried
+	sb.responses = []testResponse{{500, ""}}
+	sb.requests = nil
+	if err := c.Post(ctx, "/x", nil, nil); err == nil || len(sb.requests) != 1 {
+		t.Errorf("expected a single failed attempt, got %v requests, error %v", len(sb.requests), err)
+	}
+}
+
+func TestClientCancel(t *testing.T) {
+	sb := &seqBackend{responses: []testResponse{{500, ""}}}
+	c := NewClient("")
+	c.Driver = sb
+	c.MaxRetries = 5
+	c.Backoff = func(int) time.Duration { return time.Hour }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := c.Get(ctx, "/x", nil, nil); err != context.DeadlineExceeded {
+		t.Fatalf("expected the deadline to be exceeded, got %v", err)
+	}
+
+	if len(sb.requests) != 1 {
+		t.Errorf("expected 1 attempt, got %v", len(sb.requests))
+	}
+}
+
+func TestClientNoResponse(t *testing.T) {
+	sb := &stubBackend{}
+	sb.Response(200, `{}`)
+	c := NewClient("")
+	c.Driver = sb
+	c.OnResponse(func(*Request, *Response, error) (*Response, error) {
+		return nil, nil
+	})
+
+	var result struct{}
+	if err := c.Get(context.Background(), "/x", nil, &result); err == nil ||
+		err.Error() != "GET /x: no response" {
+		t.Errorf("expected a missing response error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	gourl "net/url"
 	"strings"
 	"time"
@@ -14,8 +15,13 @@ func SetDriver(drv Driver) {
 	driver = drv
 }
 
-func Do(req *Request) (*Response, error) {
-	return driver.Do(req)
+// Do sends a request through the driver, the request is aborted when ctx is done
+func Do(ctx context.Context, req *Request) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return driver.Do(req.WithContext(ctx))
 }
 
 type (
@@ -28,8 +34,12 @@ type (
 		URL             *gourl.URL
 		Timeout         time.Duration
 		WithCredentials bool
+
+		ctx context.Context
 	}
 
+	// Driver sends requests, it must abort a request when its context is done
+	// and give up after its Timeout
 	Driver interface {
 		Do(*Request) (*Response, error)
 	}
@@ -86,6 +96,23 @@ func (h Header) String() (s string) {
 	return
 }
 
+// ParseHeader parses raw "Key: value" header lines separated by CRLF or LF,
+// lines without a colon are ignored
+func ParseHeader(raw string) Header {
+	header := make(Header)
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		pos := strings.IndexByte(line, ':')
+		if pos <= 0 {
+			continue
+		}
+
+		header.Add(strings.TrimSpace(line[:pos]), strings.TrimSpace(line[pos+1:]))
+	}
+
+	return header
+}
+
 func NewRequest(method string, url string, body []byte) (*Request, error) {
 	u, err := gourl.Parse(url)
 	if err != nil {
@@ -99,3 +126,36 @@ func NewRequest(method string, url string, body []byte) (*Request, error) {
 		Body:   body,
 	}, nil
 }
+
+// Context returns the request's context, context.Background() if it has none
+func (r *Request) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+
+	return context.Background()
+}
+
+// WithContext returns a shallow copy of the request with its context changed to ctx
+func (r *Request) WithContext(ctx context.Context) *Request {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	r2 := *r
+	r2.ctx = ctx
+	return &r2
+}
+
+// Deadline returns the time when the request must be given up, from its context's
+// deadline and its Timeout. ok is false if there's no deadline.
+func (r *Request) Deadline() (deadline time.Time, ok bool) {
+	deadline, ok = r.Context().Deadline()
+	if r.Timeout > 0 {
+		if t := time.Now().Add(r.Timeout); !ok || t.Before(deadline) {
+			deadline, ok = t, true
+		}
+	}
+
+	return
+}
@@ -3,8 +3,8 @@ package http
 import "testing"
 
 func TestHeader(t *testing.T) {
-	req, _ := NewRequest("GET", "/test")
-	h := req.Headers
+	req, _ := NewRequest("GET", "/test", nil)
+	h := req.Header
 	k := []string{"a", "b"}
 	v := []string{"v1", "v2"}
 	h.Add(k[0], v[0])
@@ -28,3 +28,14 @@ func TestHeader(t *testing.T) {
 		t.Fatalf("expected `%v`, got `%v`", "", h.Get(k[0]))
 	}
 }
+
+func TestParseHeader(t *testing.T) {
+	h := ParseHeader("Content-Type: text/plain\r\nX-Empty:\r\nbroken line\r\nX-Time: 10:30\r\n")
+	if len(h) != 3 {
+		t.Fatalf("expected 3 headers, got %v", h)
+	}
+
+	if h.Get("Content-Type") != "text/plain" || h.Get("X-Time") != "10:30" {
+		t.Errorf("unexpected values %v", h)
+	}
+}
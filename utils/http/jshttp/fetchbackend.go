@@ -0,0 +1,195 @@
+package clientside
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+	"github.com/gowade/wade/utils/http"
+)
+
+// UseFetch makes FetchBackend the driver in place of XhrBackend,
+// it must be called before any request is sent
+func UseFetch() {
+	setBackend(FetchBackend{})
+}
+
+// FetchBackend is a Driver that uses the Fetch API. Response bodies are received as binary data,
+// and DoStream returns a body that can be read as its chunks arrive.
+type FetchBackend struct {
+}
+
+// await blocks until the promise is settled
+func await(promise *js.Object) (value *js.Object, err error) {
+	settled := make(chan struct{})
+	promise.Call("then", func(v *js.Object) {
+		value = v
+		close(settled)
+	}, func(e *js.Object) {
+		err = &js.Error{Object: e}
+		close(settled)
+	})
+
+	<-settled
+	return
+}
+
+// fetch sends the request, the returned cancel function must be called once the response
+// is not used anymore
+func (b FetchBackend) fetch(r *http.Request) (resp *js.Object, ctx context.Context, cancel context.CancelFunc, err error) {
+	if deadline, ok := r.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(r.Context(), deadline)
+	} else {
+		ctx, cancel = context.WithCancel(r.Context())
+	}
+
+	if err = ctx.Err(); err != nil {
+		cancel()
+		return
+	}
+
+	controller := js.Global.Get("AbortController").New()
+	go func() {
+		<-ctx.Done()
+		controller.Call("abort")
+	}()
+
+	header := make(map[string]string)
+	for k, values := range r.Header {
+		header[k] = strings.Join(values, ",")
+	}
+
+	credentials := "same-origin"
+	if r.WithCredentials {
+		credentials = "include"
+	}
+
+	init := map[string]interface{}{
+		"method":      r.Method,
+		"headers":     header,
+		"credentials": credentials,
+		"signal":      controller.Get("signal"),
+	}
+
+	if len(r.Body) > 0 {
+		init["body"] = r.Body
+	}
+
+	resp, err = await(js.Global.Call("fetch", r.URL.String(), init))
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+
+		cancel()
+	}
+
+	return
+}
+
+func responseHeader(resp *js.Object) http.Header {
+	header := make(http.Header)
+	resp.Get("headers").Call("forEach", func(value, key string) {
+		header.Add(key, value)
+	})
+
+	return header
+}
+
+// Do sends the request and receives the whole response body
+func (b FetchBackend) Do(r *http.Request) (*http.Response, error) {
+	resp, ctx, cancel, err := b.fetch(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cancel()
+
+	buf, err := await(resp.Call("arrayBuffer"))
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+
+		return nil, err
+	}
+
+	return &http.Response{
+		Body:       js.Global.Get("Uint8Array").New(buf).Interface().([]byte),
+		StatusCode: resp.Get("status").Int(),
+		Status:     resp.Get("statusText").String(),
+		Header:     responseHeader(resp),
+	}, nil
+}
+
+// DoStream returns the response as soon as its headers are received
+func (b FetchBackend) DoStream(r *http.Request) (*http.StreamResponse, error) {
+	resp, ctx, cancel, err := b.fetch(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &bodyReader{
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	if stream := resp.Get("body"); stream != nil && stream != js.Undefined {
+		body.reader = stream.Call("getReader")
+	}
+
+	return &http.StreamResponse{
+		Body:       body,
+		StatusCode: resp.Get("status").Int(),
+		Status:     resp.Get("statusText").String(),
+		Header:     responseHeader(resp),
+	}, nil
+}
+
+// bodyReader reads a response body stream chunk by chunk
+type bodyReader struct {
+	reader *js.Object
+	chunk  []byte
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (b *bodyReader) Read(p []byte) (int, error) {
+	for len(b.chunk) == 0 {
+		if b.reader == nil {
+			return 0, io.EOF
+		}
+
+		result, err := await(b.reader.Call("read"))
+		if err != nil {
+			if ctxErr := b.ctx.Err(); ctxErr != nil {
+				return 0, ctxErr
+			}
+
+			return 0, err
+		}
+
+		if result.Get("done").Bool() {
+			b.reader = nil
+			continue
+		}
+
+		b.chunk = result.Get("value").Interface().([]byte)
+	}
+
+	n := copy(p, b.chunk)
+	b.chunk = b.chunk[n:]
+	return n, nil
+}
+
+// Close stops receiving the body
+func (b *bodyReader) Close() error {
+	if b.reader != nil {
+		b.reader.Call("cancel")
+		b.reader = nil
+	}
+
+	b.cancel()
+	return nil
+}
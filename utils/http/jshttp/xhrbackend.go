@@ -1,42 +1,94 @@
 package clientside
 
 import (
+	"context"
 	"strings"
+	"time"
 
+	"github.com/gopherjs/gopherjs/js"
 	"github.com/gowade/wade/utils/http"
 	"honnef.co/go/js/xhr"
 )
 
 func init() {
-	http.SetDriver(XhrBackend{})
+	setBackend(XhrBackend{})
 }
 
-type XhrBackend struct {
+// setBackend sets the package's driver, answering requests from the page's snapshot first
+func setBackend(drv http.Driver) {
+	http.SetDriver(drv)
+
+	if snap := pageSnapshot(); snap != nil {
+		http.UseSnapshot(snap)
+	}
 }
 
-func headers(resp *xhr.Request) http.Header {
-	header := make(http.Header)
-	str := resp.ResponseHeaders()
-	kvlist := strings.Split(str, "\u000d\u000a")
-	for _, kv := range kvlist {
-		pos := strings.Index(kv, "\u003a\u0020")
-		header.Add(kv[:pos], kv[pos+2:])
+// pageSnapshot reads the responses recorded during the server render, nil if there are none
+func pageSnapshot() *http.Snapshot {
+	doc := js.Global.Get("document")
+	if doc == js.Undefined {
+		return nil
+	}
+
+	el := doc.Call("getElementById", http.SnapshotElementID)
+	if el == nil {
+		return nil
 	}
 
-	return header
+	snap, err := http.ParseSnapshot([]byte(el.Get("textContent").String()))
+	if err != nil {
+		js.Global.Get("console").Call("warn", "wade: invalid http snapshot: "+err.Error())
+		return nil
+	}
+
+	return snap
+}
+
+// XhrBackend is a Driver that uses XMLHttpRequest, response bodies are received as text.
+// Use FetchBackend for binary or streamed responses.
+type XhrBackend struct {
 }
 
+// Do sends the request with XMLHttpRequest, the request is aborted when its context is done
 func (b XhrBackend) Do(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	req := xhr.NewRequest(r.Method, r.URL.String())
 	req.ResponseType = "text"
-	req.Timeout = int(r.Timeout.Seconds())
 	req.WithCredentials = r.WithCredentials
 
+	// XHR timeouts are in milliseconds
+	if deadline, ok := r.Deadline(); ok {
+		ms := int(time.Until(deadline) / time.Millisecond)
+		if ms < 1 {
+			return nil, context.DeadlineExceeded
+		}
+
+		req.Timeout = ms
+	}
+
 	for k, values := range r.Header {
 		req.SetRequestHeader(k, strings.Join(values, ","))
 	}
 
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			req.Abort()
+		case <-done:
+		}
+	}()
+
 	err := req.Send(r.Body)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +97,6 @@ func (b XhrBackend) Do(r *http.Request) (*http.Response, error) {
 		Body:       []byte(req.ResponseText),
 		StatusCode: req.Status,
 		Status:     req.StatusText,
-		Header:     headers(req),
+		Header:     http.ParseHeader(req.ResponseHeaders()),
 	}, nil
 }
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+)
+
+// SnapshotElementID is the id of the script element that holds
+// the responses recorded during the server render
+const SnapshotElementID = "wade-http-snapshot"
+
+// SnapshotHeaders are the response headers kept in a snapshot, the others are left out
+// because the snapshot is written in the page, where cookies or headers derived from
+// the request's credentials must not end up
+var SnapshotHeaders = []string{"Content-Type"}
+
+type (
+	// Snapshot holds responses recorded during the server render,
+	// to answer the same requests on the client
+	Snapshot struct {
+		Entries []SnapshotEntry `json:"entries"`
+	}
+
+	SnapshotEntry struct {
+		Method   string    `json:"method"`
+		URL      string    `json:"url"`
+		Body     []byte    `json:"body,omitempty"`
+		Response *Response `json:"response"`
+	}
+)
+
+func requestKey(method, url string, body []byte) string {
+	return method + " " + url + "\n" + string(body)
+}
+
+// Recorder is a Driver that records the responses of another driver
+type Recorder struct {
+	Driver
+
+	mu      sync.Mutex
+	entries []SnapshotEntry
+}
+
+// NewRecorder returns a Recorder that sends requests through drv
+func NewRecorder(drv Driver) *Recorder {
+	return &Recorder{Driver: drv}
+}
+
+func (r *Recorder) Do(req *Request) (*Response, error) {
+	resp, err := r.Driver.Do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	r.mu.Lock()
+	r.entries = append(r.entries, SnapshotEntry{
+		Method:   req.Method,
+		URL:      req.URL.String(),
+		Body:     req.Body,
+		Response: snapshotResponse(resp),
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// snapshotResponse returns a copy of the response with only the SnapshotHeaders
+func snapshotResponse(resp *Response) *Response {
+	snap := *resp
+	snap.Header = make(Header)
+	for key, values := range resp.Header {
+		for _, allowed := range SnapshotHeaders {
+			if strings.EqualFold(key, allowed) {
+				snap.Header[key] = append([]string{}, values...)
+			}
+		}
+	}
+
+	return &snap
+}
+
+// Snapshot returns the responses recorded so far
+func (r *Recorder) Snapshot() *Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &Snapshot{
+		Entries: append([]SnapshotEntry{}, r.entries...),
+	}
+}
+
+// WriteScript writes the recorded responses as a JSON script element, to be put in the page
+// so that the client reads them on startup
+func (r *Recorder) WriteScript(w io.Writer) error {
+	// json.Marshal escapes <, > and &, the data cannot close the script element
+	data, err := json.Marshal(r.Snapshot())
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, `<script type="application/json" id="`+SnapshotElementID+`">`+
+		string(data)+`</script>`)
+	return err
+}
+
+// ParseSnapshot parses the content of a snapshot script element
+func ParseSnapshot(data []byte) (*Snapshot, error) {
+	snap := &Snapshot{}
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, err
+	}
+
+	return snap, nil
+}
+
+// SnapshotDriver is a Driver that answers requests from a snapshot, each recorded
+// response answers one matching request. Other requests are sent through the wrapped driver.
+type SnapshotDriver struct {
+	Driver
+
+	mu        sync.Mutex
+	responses map[string][]*Response
+}
+
+// NewSnapshotDriver returns a SnapshotDriver that uses drv for the requests
+// not found in the snapshot
+func NewSnapshotDriver(drv Driver, snap *Snapshot) *SnapshotDriver {
+	responses := make(map[string][]*Response)
+	for _, e := range snap.Entries {
+		key := requestKey(e.Method, e.URL, e.Body)
+		responses[key] = append(responses[key], e.Response)
+	}
+
+	return &SnapshotDriver{
+		Driver:    drv,
+		responses: responses,
+	}
+}
+
+// take removes and returns the recorded response for the request, nil if there's none
+func (d *SnapshotDriver) take(req *Request) *Response {
+	key := requestKey(req.Method, req.URL.String(), req.Body)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	l := d.responses[key]
+	if len(l) == 0 {
+		return nil
+	}
+
+	d.responses[key] = l[1:]
+	return l[0]
+}
+
+func (d *SnapshotDriver) Do(req *Request) (*Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	if resp := d.take(req); resp != nil {
+		return resp, nil
+	}
+
+	return d.Driver.Do(req)
+}
+
+// DoStream answers the request from the snapshot, or streams it through the wrapped driver
+func (d *SnapshotDriver) DoStream(req *Request) (*StreamResponse, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	if resp := d.take(req); resp != nil {
+		return doStream(&staticDriver{resp}, req)
+	}
+
+	return doStream(d.Driver, req)
+}
+
+// staticDriver answers every request with the same response
+type staticDriver struct {
+	resp *Response
+}
+
+func (d *staticDriver) Do(*Request) (*Response, error) {
+	return d.resp, nil
+}
+
+// UseSnapshot makes Do answer requests from the snapshot before using the current driver
+func UseSnapshot(snap *Snapshot) {
+	SetDriver(NewSnapshotDriver(driver, snap))
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// urlBackend answers with the URL of the request and counts the requests
+type urlBackend struct {
+	count int
+}
+
+func (b *urlBackend) Do(r *Request) (*Response, error) {
+	b.count++
+	return &Response{
+		Body:       []byte(r.URL.String()),
+		StatusCode: 200,
+		Header: Header{
+			"Content-Type": {"text/plain"},
+			"Set-Cookie":   {"session=secret"},
+			"X-User":       {"bob"},
+		},
+	}, nil
+}
+
+func TestSnapshot(t *testing.T) {
+	rec := NewRecorder(&urlBackend{})
+	for _, url := range []string{"/a", "/b?page=2", "/a"} {
+		req, _ := NewRequest("GET", url, nil)
+		if _, err := rec.Do(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := rec.WriteScript(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	script := buf.String()
+	if strings.Contains(script, "secret") || strings.Contains(script, "bob") {
+		t.Errorf("only the allowed headers should be recorded, got %v", script)
+	}
+
+	data := script[strings.Index(script, ">")+1 : strings.LastIndex(script, "</script>")]
+	snap, err := ParseSnapshot([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &urlBackend{}
+	drv := NewSnapshotDriver(backend, snap)
+	for _, url := range []string{"/b?page=2", "/a", "/a", "/a", "/b?page=3"} {
+		req, _ := NewRequest("GET", url, nil)
+		resp, err := drv.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(resp.Body) != url {
+			t.Errorf("expected the response for %v, got %s", url, resp.Body)
+		}
+	}
+
+	// the third request to /a and the one to /b?page=3 weren't recorded
+	if backend.count != 2 {
+		t.Errorf("expected 2 requests to miss the snapshot, got %v", backend.count)
+	}
+
+	req, _ := NewRequest("POST", "/a", nil)
+	if _, err := drv.Do(req); err != nil || backend.count != 3 {
+		t.Errorf("a request with another method should miss the snapshot")
+	}
+
+	req, _ = NewRequest("GET", "/a", nil)
+	resp, _ := rec.Do(req)
+	if resp.Header.Get("Set-Cookie") == "" {
+		t.Errorf("the recorder should return the full response")
+	}
+}
@@ -3,6 +3,7 @@ package serverside
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -16,7 +17,21 @@ type ServerBackend struct {
 	ClientReq *http.Request
 }
 
+// Do serves the request with the server's handler, the headers of the client's
+// request are passed on. The handler gets the request's context, Do returns
+// as soon as the context is done or the request's timeout expires.
 func (b *ServerBackend) Do(wr *wadehttp.Request) (*wadehttp.Response, error) {
+	ctx := wr.Context()
+	if deadline, ok := wr.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	buf := bytes.NewBufferString("")
 	b.ClientReq.Write(buf)
 
@@ -25,12 +40,23 @@ func (b *ServerBackend) Do(wr *wadehttp.Request) (*wadehttp.Response, error) {
 		return nil, err
 	}
 
+	req = req.WithContext(ctx)
 	req.Method = wr.Method
 	req.URL = wr.URL
 	req.Body = ioutil.NopCloser(bytes.NewBuffer(wr.Body))
 
 	resp := httptest.NewRecorder()
-	b.Server.ServeHTTP(resp, req)
+	served := make(chan struct{})
+	go func() {
+		b.Server.ServeHTTP(resp, req)
+		close(served)
+	}()
+
+	select {
+	case <-served:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
 
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+)
+
+type (
+	// StreamResponse is a response whose body can be read as it arrives,
+	// the body must be closed
+	StreamResponse struct {
+		Body       io.ReadCloser
+		Status     string
+		StatusCode int
+		Header     Header
+	}
+
+	// StreamDriver is a Driver that can return a response before its body is received
+	StreamDriver interface {
+		Driver
+		DoStream(*Request) (*StreamResponse, error)
+	}
+)
+
+// DoStream sends a request and returns the response as soon as its headers are received.
+// If the driver is not a StreamDriver, the whole body is received before DoStream returns.
+// The body's reads fail once ctx is done.
+func DoStream(ctx context.Context, req *Request) (*StreamResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return doStream(driver, req.WithContext(ctx))
+}
+
+func doStream(drv Driver, req *Request) (*StreamResponse, error) {
+	if sd, ok := drv.(StreamDriver); ok {
+		return sd.DoStream(req)
+	}
+
+	resp, err := drv.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamResponse{
+		Body:       ioutil.NopCloser(bytes.NewReader(resp.Body)),
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Catalog holds the translations for a locale, keyed by source text
+type Catalog struct {
+	Locale   string             `json:"locale"`
+	Messages map[string]Message `json:"messages"`
+}
+
+// Message is a translation, it has one form per plural category of its locale
+// if its source text has plural forms. It is encoded to JSON as a string if it has
+// a single form, as an array of strings otherwise.
+type Message []string
+
+// translated tells whether the message has a non-empty translation
+func (m Message) translated() bool {
+	for _, form := range m {
+		if form != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m Message) MarshalJSON() ([]byte, error) {
+	if len(m) == 1 {
+		return json.Marshal(m[0])
+	}
+
+	return json.Marshal([]string(m))
+}
+
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*m = Message{s}
+		return nil
+	}
+
+	var forms []string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return fmt.Errorf("a message must be a string or an array of strings, got %s", data)
+	}
+
+	*m = forms
+	return nil
+}
+
+// ParseCatalog parses a JSON catalog
+func ParseCatalog(data []byte) (*Catalog, error) {
+	c := &Catalog{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+
+	if c.Messages == nil {
+		c.Messages = make(map[string]Message)
+	}
+
+	return c, nil
+}
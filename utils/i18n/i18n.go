@@ -0,0 +1,171 @@
+// Package i18n translates messages with catalogs, one per locale.
+//
+// A message is identified by its source text, in which {name} is a placeholder
+// for a value. The source text of a message with plural forms has its forms
+// separated by |, e.g "{n} new message|{n} new messages".
+package i18n
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SourceLocale is the locale of the source texts, used when a message is not translated
+var SourceLocale = "en"
+
+var (
+	mu        sync.RWMutex
+	locale    string
+	catalogs  = map[string]*Catalog{}
+	listeners []func(locale string)
+)
+
+// AddCatalog adds the translations of a catalog to those of its locale
+func AddCatalog(c *Catalog) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	cur, ok := catalogs[c.Locale]
+	if !ok {
+		cur = &Catalog{
+			Locale:   c.Locale,
+			Messages: make(map[string]Message),
+		}
+		catalogs[c.Locale] = cur
+	}
+
+	for id, m := range c.Messages {
+		cur.Messages[id] = m
+	}
+}
+
+// Locale returns the active locale
+func Locale() string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if locale == "" {
+		return SourceLocale
+	}
+
+	return locale
+}
+
+// SetLocale changes the active locale and calls the functions registered with OnLocaleChange
+func SetLocale(l string) {
+	mu.Lock()
+	changed := l != locale
+	locale = l
+	fns := append([]func(string){}, listeners...)
+	mu.Unlock()
+
+	if changed {
+		for _, fn := range fns {
+			fn(l)
+		}
+	}
+}
+
+// OnLocaleChange registers a function to be called when the active locale changes
+func OnLocaleChange(fn func(locale string)) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	listeners = append(listeners, fn)
+}
+
+// lookup returns the translation of a message and the locale it is from,
+// "fr-CA" falls back to "fr"
+func lookup(id string) (Message, string) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	l := locale
+	for l != "" {
+		if c, ok := catalogs[l]; ok {
+			if m := c.Messages[id]; m.translated() {
+				return m, l
+			}
+		}
+
+		i := strings.LastIndexAny(l, "-_")
+		if i == -1 {
+			break
+		}
+
+		l = l[:i]
+	}
+
+	return nil, ""
+}
+
+// T translates a message to the active locale, args are the values of the
+// placeholders in the order they first appear in the source text
+func T(id string, args ...interface{}) string {
+	text := id
+	if m, _ := lookup(id); m != nil {
+		text = m[0]
+	}
+
+	return format(text, placeholders(id), args)
+}
+
+// TN translates a message with plural forms, the form is chosen by the plural rule
+// of the locale for count
+func TN(id string, count int, args ...interface{}) string {
+	forms := Message(strings.Split(id, "|"))
+	l := SourceLocale
+	if m, ml := lookup(id); m != nil {
+		forms, l = m, ml
+	}
+
+	i := pluralRule(l)(count)
+	if i >= len(forms) {
+		i = len(forms) - 1
+	}
+
+	return format(forms[i], placeholders(id), args)
+}
+
+// placeholders returns the names of the placeholders in a source text, in order of first appearance
+func placeholders(text string) []string {
+	var names []string
+	seen := map[string]bool{}
+	for {
+		start := strings.IndexByte(text, '{')
+		if start == -1 {
+			return names
+		}
+
+		end := strings.IndexByte(text[start:], '}')
+		if end == -1 {
+			return names
+		}
+
+		name := text[start+1 : start+end]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+
+		text = text[start+end+1:]
+	}
+}
+
+// format replaces the placeholders in text with the values of args,
+// unknown placeholders are left as is
+func format(text string, names []string, args []interface{}) string {
+	if len(names) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+
+	pairs := make([]string, 0, 2*len(names))
+	for i, name := range names {
+		if i < len(args) {
+			pairs = append(pairs, "{"+name+"}", fmt.Sprint(args[i]))
+		}
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
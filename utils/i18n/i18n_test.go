@@ -0,0 +1,78 @@
+package i18n
+
+import "testing"
+
+func TestTranslate(t *testing.T) {
+	c, err := ParseCatalog([]byte(`{
+		"locale": "fr",
+		"messages": {
+			"Hello {name}, welcome to {site}": "{site} vous souhaite la bienvenue, {name}",
+			"{n} new message|{n} new messages": ["{n} nouveau message", "{n} nouveaux messages"],
+			"Untranslated": ""
+		}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	AddCatalog(c)
+
+	var changes []string
+	OnLocaleChange(func(l string) {
+		changes = append(changes, l)
+	})
+
+	cases := []struct {
+		locale, expected string
+		translate        func() string
+	}{
+		{"", "Hello Bob, welcome to Wade", func() string {
+			return T("Hello {name}, welcome to {site}", "Bob", "Wade")
+		}},
+		{"fr-CA", "Wade vous souhaite la bienvenue, Bob", func() string {
+			return T("Hello {name}, welcome to {site}", "Bob", "Wade")
+		}},
+		{"fr", "0 nouveau message", func() string {
+			return TN("{n} new message|{n} new messages", 0, 0)
+		}},
+		{"fr", "2 nouveaux messages", func() string {
+			return TN("{n} new message|{n} new messages", 2, 2)
+		}},
+		{"fr", "Untranslated", func() string {
+			return T("Untranslated")
+		}},
+		{"en", "0 new messages", func() string {
+			return TN("{n} new message|{n} new messages", 0, 0)
+		}},
+	}
+
+	for _, c := range cases {
+		SetLocale(c.locale)
+		if s := c.translate(); s != c.expected {
+			t.Errorf("%v: expected %q, got %q", c.locale, c.expected, s)
+		}
+	}
+
+	if len(changes) != 3 {
+		t.Errorf("expected 3 locale changes, got %v", changes)
+	}
+}
+
+func TestPluralRules(t *testing.T) {
+	expected := map[int]int{1: 0, 3: 1, 5: 2, 11: 2, 21: 0, 22: 1}
+	for n, form := range expected {
+		if i := pluralRule("ru-RU")(n); i != form {
+			t.Errorf("ru: expected form %v for %v, got %v", form, n, i)
+		}
+	}
+
+	if i := pluralRule("ja")(1); i != 0 {
+		t.Errorf("ja: expected a single form, got %v", i)
+	}
+
+	for locale, forms := range map[string]int{"en": 2, "fr": 2, "ja": 1, "ru": 3, "pl": 3} {
+		if n := PluralForms(locale); n != forms {
+			t.Errorf("%v: expected %v plural forms, got %v", locale, forms, n)
+		}
+	}
+}
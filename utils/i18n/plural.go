@@ -0,0 +1,114 @@
+package i18n
+
+import "strings"
+
+// PluralRule returns the index of the plural form to use for n
+type PluralRule func(n int) int
+
+var pluralRules = map[string]PluralRule{}
+
+func init() {
+	oneOther := func(n int) int {
+		if n == 1 {
+			return 0
+		}
+
+		return 1
+	}
+
+	zeroOne := func(n int) int {
+		if n == 0 || n == 1 {
+			return 0
+		}
+
+		return 1
+	}
+
+	none := func(int) int {
+		return 0
+	}
+
+	// one, few, many
+	slavic := func(n int) int {
+		switch {
+		case n%10 == 1 && n%100 != 11:
+			return 0
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return 1
+		}
+
+		return 2
+	}
+
+	for _, l := range []string{"en", "de", "nl", "sv", "da", "no", "es", "it", "el", "fi", "hu", "tr"} {
+		pluralRules[l] = oneOther
+	}
+
+	for _, l := range []string{"fr", "pt"} {
+		pluralRules[l] = zeroOne
+	}
+
+	for _, l := range []string{"ja", "zh", "ko", "vi", "th", "id"} {
+		pluralRules[l] = none
+	}
+
+	for _, l := range []string{"ru", "uk"} {
+		pluralRules[l] = slavic
+	}
+
+	pluralRules["pl"] = func(n int) int {
+		if n == 1 {
+			return 0
+		}
+
+		if i := slavic(n); i == 1 {
+			return 1
+		}
+
+		return 2
+	}
+}
+
+// RegisterPluralRule sets the plural rule of a language or a locale
+func RegisterPluralRule(locale string, rule PluralRule) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	pluralRules[locale] = rule
+}
+
+// pluralRule returns the rule of a locale, or of its language,
+// the English rule if there's none
+func pluralRule(locale string) PluralRule {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for l := locale; l != ""; {
+		if rule, ok := pluralRules[l]; ok {
+			return rule
+		}
+
+		i := strings.LastIndexAny(l, "-_")
+		if i == -1 {
+			break
+		}
+
+		l = l[:i]
+	}
+
+	return pluralRules["en"]
+}
+
+// PluralForms returns the number of plural forms of a locale,
+// the highest form its rule gives for the counts up to 1000, plus one
+func PluralForms(locale string) int {
+	rule := pluralRule(locale)
+	forms := 1
+	for n := 0; n <= 1000; n++ {
+		if i := rule(n); i >= forms {
+			forms = i + 1
+		}
+	}
+
+	return forms
+}
@@ -0,0 +1,50 @@
+package storage
+
+import "sync"
+
+// Memory is a Driver that keeps values in memory, for the server and for tests
+type Memory struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		values: make(map[string]string),
+	}
+}
+
+func (m *Memory) Get(key string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	v, ok := m.values[key]
+	return v, ok
+}
+
+func (m *Memory) Set(key, value string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.values[key] = value
+	return nil
+}
+
+func (m *Memory) Delete(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.values, key)
+}
+
+func (m *Memory) Keys() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0, len(m.values))
+	for k := range m.values {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
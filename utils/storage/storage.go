@@ -0,0 +1,181 @@
+// Package storage provides key-value stores that persist in the browser,
+// local and session storage, through a pluggable driver
+package storage
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+)
+
+var (
+	local   *Store
+	session *Store
+)
+
+func init() {
+	SetDriver(NewMemory(), NewMemory())
+}
+
+// SetDriver sets the drivers of the local and session stores,
+// the listeners of the previous stores are not kept
+func SetDriver(localDrv, sessionDrv Driver) {
+	local = newStore(localDrv)
+	session = newStore(sessionDrv)
+}
+
+// Local returns the store that persists across browser sessions, like localStorage
+func Local() *Store {
+	return local
+}
+
+// Session returns the store that is cleared when the browser session ends, like sessionStorage
+func Session() *Store {
+	return session
+}
+
+type (
+	// Driver stores string values
+	Driver interface {
+		Get(key string) (value string, ok bool)
+		Set(key, value string) error
+		Delete(key string)
+		Keys() []string
+	}
+
+	// Notifier is a Driver that reports changes made outside of the program,
+	// e.g by another browser tab
+	Notifier interface {
+		Driver
+		OnChange(func(Change))
+	}
+
+	// Change describes a modification of a key, Key is empty if the whole storage has been cleared
+	Change struct {
+		Key      string
+		OldValue string
+		NewValue string
+		Deleted  bool
+	}
+)
+
+// Store is a key-value store, values are strings or encoded to JSON
+type Store struct {
+	drv Driver
+
+	mu        sync.Mutex
+	listeners map[int]func(Change)
+	nextID    int
+}
+
+func newStore(drv Driver) *Store {
+	s := &Store{
+		drv:       drv,
+		listeners: make(map[int]func(Change)),
+	}
+
+	if n, ok := drv.(Notifier); ok {
+		n.OnChange(s.notify)
+	}
+
+	return s
+}
+
+// Get returns the value of a key, ok is false if the key doesn't exist
+func (s *Store) Get(key string) (value string, ok bool) {
+	return s.drv.Get(key)
+}
+
+// Set sets the value of a key, an error is returned if the storage is full or unavailable
+func (s *Store) Set(key, value string) error {
+	old, _ := s.drv.Get(key)
+	if err := s.drv.Set(key, value); err != nil {
+		return err
+	}
+
+	s.notify(Change{
+		Key:      key,
+		OldValue: old,
+		NewValue: value,
+	})
+
+	return nil
+}
+
+// Delete removes a key
+func (s *Store) Delete(key string) {
+	old, ok := s.drv.Get(key)
+	if !ok {
+		return
+	}
+
+	s.drv.Delete(key)
+	s.notify(Change{
+		Key:      key,
+		OldValue: old,
+		Deleted:  true,
+	})
+}
+
+// Keys returns the stored keys in sorted order
+func (s *Store) Keys() []string {
+	keys := s.drv.Keys()
+	sort.Strings(keys)
+	return keys
+}
+
+// GetJSON decodes the JSON value of a key into v, ok is false if the key doesn't exist
+func (s *Store) GetJSON(key string, v interface{}) (ok bool, err error) {
+	value, ok := s.drv.Get(key)
+	if !ok {
+		return false, nil
+	}
+
+	return true, json.Unmarshal([]byte(value), v)
+}
+
+// SetJSON sets the value of a key to v encoded to JSON
+func (s *Store) SetJSON(key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.Set(key, string(data))
+}
+
+// Watch calls fn for every change, including those made by other browser tabs
+// if the driver reports them. It returns a function that stops watching.
+func (s *Store) Watch(fn func(Change)) (stop func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.nextID
+	s.nextID++
+	s.listeners[id] = fn
+
+	return func() {
+		s.mu.Lock()
+		delete(s.listeners, id)
+		s.mu.Unlock()
+	}
+}
+
+func (s *Store) notify(c Change) {
+	s.mu.Lock()
+	ids := make([]int, 0, len(s.listeners))
+	for id := range s.listeners {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	fns := make([]func(Change), len(ids))
+	for i, id := range ids {
+		fns[i] = s.listeners[id]
+	}
+	s.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(c)
+	}
+}
@@ -0,0 +1,49 @@
+package storage
+
+import "testing"
+
+func TestStore(t *testing.T) {
+	SetDriver(NewMemory(), NewMemory())
+	s := Local()
+
+	var changes []Change
+	stop := s.Watch(func(c Change) {
+		changes = append(changes, c)
+	})
+
+	type prefs struct {
+		Theme    string
+		PageSize int
+	}
+
+	if err := s.SetJSON("prefs", prefs{"dark", 20}); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Set("lang", "vi")
+	var p prefs
+	if ok, err := s.GetJSON("prefs", &p); !ok || err != nil || p.PageSize != 20 {
+		t.Fatalf("unexpected stored prefs %+v, %v, %v", p, ok, err)
+	}
+
+	if keys := s.Keys(); len(keys) != 2 || keys[0] != "lang" || keys[1] != "prefs" {
+		t.Errorf("unexpected keys %v", keys)
+	}
+
+	if _, ok := Session().Get("lang"); ok {
+		t.Errorf("the session store should be separate from the local store")
+	}
+
+	s.Delete("lang")
+	s.Delete("missing")
+	stop()
+	s.Set("x", "y")
+
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+
+	if c := changes[2]; c.Key != "lang" || !c.Deleted || c.OldValue != "vi" {
+		t.Errorf("unexpected change %+v", c)
+	}
+}
@@ -0,0 +1,125 @@
+package clientside
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/gopherjs/gopherjs/js"
+	"github.com/gowade/wade/utils/ws"
+)
+
+func init() {
+	ws.SetDriver(WebSocketBackend{})
+}
+
+// WebSocketBackend is a Driver that uses the browser's WebSocket
+type WebSocketBackend struct {
+}
+
+// Dial opens a WebSocket and waits until it is open
+func (b WebSocketBackend) Dial(ctx context.Context, url string) (ws.Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	c := &conn{
+		socket: js.Global.Get("WebSocket").New(url),
+		ready:  make(chan struct{}, 1),
+	}
+
+	c.socket.Set("binaryType", "arraybuffer")
+
+	opened := make(chan struct{})
+	failed := make(chan struct{})
+	c.socket.Set("onopen", func() {
+		close(opened)
+	})
+
+	c.socket.Set("onmessage", func(ev *js.Object) {
+		data := ev.Get("data")
+		if data.Get("constructor") == js.Global.Get("ArrayBuffer") {
+			c.queue = append(c.queue, ws.Message{
+				Data:   js.Global.Get("Uint8Array").New(data).Interface().([]byte),
+				Binary: true,
+			})
+		} else {
+			c.queue = append(c.queue, ws.TextMessage(data.String()))
+		}
+
+		c.notify()
+	})
+
+	c.socket.Set("onclose", func(ev *js.Object) {
+		if !c.closed {
+			c.closed = true
+			select {
+			case <-opened:
+			default:
+				close(failed)
+			}
+		}
+
+		c.notify()
+	})
+
+	select {
+	case <-opened:
+		return c, nil
+	case <-failed:
+		return nil, errors.New("ws: cannot connect to " + url)
+	case <-ctx.Done():
+		c.socket.Call("close")
+		return nil, ctx.Err()
+	}
+}
+
+type conn struct {
+	socket *js.Object
+	queue  []ws.Message
+	closed bool
+	ready  chan struct{}
+}
+
+// notify wakes up a pending ReadMessage, event handlers cannot block
+func (c *conn) notify() {
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (c *conn) ReadMessage() (ws.Message, error) {
+	for {
+		if len(c.queue) > 0 {
+			m := c.queue[0]
+			c.queue = c.queue[1:]
+			return m, nil
+		}
+
+		if c.closed {
+			return ws.Message{}, io.EOF
+		}
+
+		<-c.ready
+	}
+}
+
+func (c *conn) WriteMessage(m ws.Message) error {
+	if c.closed {
+		return io.ErrClosedPipe
+	}
+
+	if m.Binary {
+		c.socket.Call("send", m.Data)
+	} else {
+		c.socket.Call("send", string(m.Data))
+	}
+
+	return nil
+}
+
+func (c *conn) Close() error {
+	c.socket.Call("close")
+	return nil
+}
@@ -0,0 +1,258 @@
+package ws
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gowade/wade/utils/http"
+)
+
+type outgoing struct {
+	msg    Message
+	result chan error
+}
+
+// Socket is a WebSocket connection that is reopened after it's lost,
+// with a delay given by Backoff. It's usually created with NewSocket,
+// a Socket literal needs at least a URL and has no delay by default.
+type Socket struct {
+	Driver  Driver
+	URL     string
+	Backoff http.Backoff
+
+	// MaxRetries is the number of consecutive failed connection attempts
+	// before the socket gives up, 0 for no limit
+	MaxRetries int
+
+	// OnOpen is called each time a connection is opened
+	OnOpen func()
+
+	initOnce  sync.Once
+	messages  chan Message
+	out       chan outgoing
+	closed    chan struct{}
+	closeOnce sync.Once
+	cancel    context.CancelFunc
+
+	mu        sync.Mutex
+	err       error
+	connected bool
+}
+
+// NewSocket returns a socket to url that uses the package's driver
+func NewSocket(url string) *Socket {
+	return &Socket{
+		URL:     url,
+		Backoff: http.ExponentialBackoff(500*time.Millisecond, 30*time.Second),
+	}
+}
+
+// init makes the channels, the socket may not have been created by NewSocket
+func (s *Socket) init() {
+	s.initOnce.Do(func() {
+		s.messages = make(chan Message, 16)
+		s.out = make(chan outgoing)
+		s.closed = make(chan struct{})
+	})
+}
+
+func (s *Socket) driver() Driver {
+	if s.Driver != nil {
+		return s.Driver
+	}
+
+	return driver
+}
+
+// Connect starts connecting in the background, the socket is closed when ctx is done.
+// A socket can only be connected once, ErrConnected is returned after the first call.
+func (s *Socket) Connect(ctx context.Context) error {
+	s.init()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.closed:
+		return ErrClosed
+	default:
+	}
+
+	if s.connected {
+		return ErrConnected
+	}
+
+	s.connected = true
+	ctx, s.cancel = context.WithCancel(ctx)
+	go s.run(ctx)
+	return nil
+}
+
+// Messages returns the channel of received messages, it is closed once the socket is closed
+func (s *Socket) Messages() <-chan Message {
+	s.init()
+	return s.messages
+}
+
+// Send sends a message, it blocks while the socket is connecting.
+// A message is lost if the connection is lost before it is sent.
+func (s *Socket) Send(m Message) error {
+	s.init()
+	o := outgoing{m, make(chan error, 1)}
+	select {
+	case s.out <- o:
+		return <-o.result
+	case <-s.closed:
+		return ErrClosed
+	}
+}
+
+// SendJSON sends v encoded to JSON in a text message
+func (s *Socket) SendJSON(v interface{}) error {
+	m, err := JSONMessage(v)
+	if err != nil {
+		return err
+	}
+
+	return s.Send(m)
+}
+
+// Close closes the socket and its connection
+func (s *Socket) Close() error {
+	s.init()
+	s.shutdown(nil)
+
+	s.mu.Lock()
+	cancel := s.cancel
+	if !s.connected {
+		// there's no run loop to close the messages
+		s.connected = true
+		close(s.messages)
+	}
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	return nil
+}
+
+// Err returns the error the socket has given up with, nil if it is open
+// or has been closed with Close
+func (s *Socket) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+func (s *Socket) shutdown(err error) {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+
+		close(s.closed)
+	})
+}
+
+func (s *Socket) run(ctx context.Context) {
+	defer close(s.messages)
+
+	failures := 0
+	for {
+		conn, err := s.driver().Dial(ctx, s.URL)
+		if err != nil {
+			if ctx.Err() != nil {
+				s.shutdown(ctx.Err())
+				return
+			}
+
+			failures++
+			if s.MaxRetries > 0 && failures > s.MaxRetries {
+				s.shutdown(err)
+				return
+			}
+
+			if !s.wait(ctx, failures) {
+				return
+			}
+
+			continue
+		}
+
+		failures = 0
+		if s.OnOpen != nil {
+			s.OnOpen()
+		}
+
+		if !s.serve(ctx, conn) || !s.wait(ctx, 1) {
+			return
+		}
+	}
+}
+
+// wait sleeps before the given reconnection attempt, it returns false if the socket is closed meanwhile
+func (s *Socket) wait(ctx context.Context, attempt int) bool {
+	var delay time.Duration
+	if s.Backoff != nil {
+		delay = s.Backoff(attempt)
+	}
+
+	select {
+	case <-time.After(delay):
+		return true
+	case <-ctx.Done():
+		s.shutdown(ctx.Err())
+		return false
+	}
+}
+
+// serve passes messages through the connection until it is lost, in which case it returns true,
+// or the socket is closed
+func (s *Socket) serve(ctx context.Context, conn Conn) bool {
+	stop := make(chan struct{})
+	readErr := make(chan error, 1)
+	readerDone := make(chan struct{})
+	go func() {
+		defer close(readerDone)
+		for {
+			m, err := conn.ReadMessage()
+			if err != nil {
+				readErr <- err
+				return
+			}
+
+			select {
+			case s.messages <- m:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	defer func() {
+		close(stop)
+		conn.Close()
+		<-readerDone
+	}()
+
+	for {
+		select {
+		case o := <-s.out:
+			err := conn.WriteMessage(o.msg)
+			o.result <- err
+			if err != nil {
+				return true
+			}
+
+		case <-readErr:
+			return true
+
+		case <-ctx.Done():
+			s.shutdown(ctx.Err())
+			return false
+		}
+	}
+}
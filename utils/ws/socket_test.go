@@ -0,0 +1,149 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// pipeConn echoes the messages written to it
+type pipeConn struct {
+	messages chan Message
+	closed   chan struct{}
+}
+
+func newPipeConn() *pipeConn {
+	return &pipeConn{
+		messages: make(chan Message, 8),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (c *pipeConn) ReadMessage() (Message, error) {
+	select {
+	case m := <-c.messages:
+		return m, nil
+	case <-c.closed:
+		return Message{}, io.EOF
+	}
+}
+
+func (c *pipeConn) WriteMessage(m Message) error {
+	c.messages <- m
+	return nil
+}
+
+func (c *pipeConn) Close() error {
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+
+	return nil
+}
+
+// flakyDriver fails the given number of dials before each successful one
+type flakyDriver struct {
+	failures int
+	dials    int
+	conns    chan *pipeConn
+}
+
+func (d *flakyDriver) Dial(ctx context.Context, url string) (Conn, error) {
+	d.dials++
+	if d.dials <= d.failures {
+		return nil, errors.New("connection refused")
+	}
+
+	d.dials = 0
+	c := newPipeConn()
+	d.conns <- c
+	return c, nil
+}
+
+func TestSocketReconnect(t *testing.T) {
+	drv := &flakyDriver{failures: 2, conns: make(chan *pipeConn, 2)}
+	s := NewSocket("ws://test")
+	s.Driver = drv
+	s.Backoff = func(int) time.Duration { return time.Millisecond }
+	s.Connect(context.Background())
+	defer s.Close()
+
+	type event struct {
+		Kind string `json:"kind"`
+	}
+
+	if err := s.SendJSON(event{"start"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var ev event
+	if err := (<-s.Messages()).JSON(&ev); err != nil || ev.Kind != "start" {
+		t.Fatalf("expected the start event, got %+v, %v", ev, err)
+	}
+
+	(<-drv.conns).Close()
+	<-drv.conns
+	if err := s.Send(TextMessage("again")); err != nil {
+		t.Fatal(err)
+	}
+
+	if m := <-s.Messages(); m.Text() != "again" {
+		t.Fatalf("expected a message after reconnection, got %q", m.Text())
+	}
+}
+
+func TestSocketGiveUp(t *testing.T) {
+	s := NewSocket("ws://test")
+	s.Driver = &flakyDriver{failures: 10}
+	s.Backoff = nil
+	s.MaxRetries = 3
+	s.Connect(context.Background())
+
+	if _, ok := <-s.Messages(); ok {
+		t.Fatal("expected the messages channel to be closed")
+	}
+
+	if s.Err() == nil || s.Send(TextMessage("x")) != ErrClosed {
+		t.Errorf("expected the socket to be closed with an error, got %v", s.Err())
+	}
+}
+
+func TestSocketLiteral(t *testing.T) {
+	drv := &flakyDriver{conns: make(chan *pipeConn, 1)}
+	s := &Socket{URL: "ws://test", Driver: drv}
+	if err := s.Connect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Connect(context.Background()); err != ErrConnected {
+		t.Errorf("expected a second Connect to fail, got %v", err)
+	}
+
+	if err := s.Send(TextMessage("hi")); err != nil {
+		t.Fatal(err)
+	}
+
+	if m := <-s.Messages(); m.Text() != "hi" {
+		t.Errorf("unexpected message %q", m.Text())
+	}
+
+	s.Close()
+	if _, ok := <-s.Messages(); ok {
+		t.Error("expected the messages channel to be closed")
+	}
+
+	// a socket that is never connected
+	s = &Socket{URL: "ws://test"}
+	s.Close()
+	if s.Send(TextMessage("x")) != ErrClosed || s.Connect(context.Background()) != ErrClosed {
+		t.Error("expected the socket to be closed")
+	}
+
+	if _, ok := <-s.Messages(); ok {
+		t.Error("expected the messages channel to be closed")
+	}
+}
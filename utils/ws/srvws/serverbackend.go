@@ -0,0 +1,147 @@
+package serverside
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	gourl "net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/gowade/wade/utils/ws"
+)
+
+// handshakeHeaders are set by the WebSocket client, they are not copied from the client's request
+var handshakeHeaders = []string{
+	"Upgrade",
+	"Connection",
+	"Sec-Websocket-Key",
+	"Sec-Websocket-Version",
+	"Sec-Websocket-Extensions",
+	"Sec-Websocket-Protocol",
+	"Content-Length",
+}
+
+// ServerBackend is a Driver that connects straight to the server's handler, without the network.
+// The headers of the client's request, such as cookies, are passed on.
+type ServerBackend struct {
+	Server    http.Handler
+	ClientReq *http.Request
+}
+
+// Dial performs the WebSocket handshake with the server's handler over an in-memory pipe
+func (b *ServerBackend) Dial(ctx context.Context, url string) (ws.Conn, error) {
+	u, err := gourl.Parse(url)
+	if err != nil {
+		return nil, err
+	}
+
+	// there's no TLS in-process
+	u.Scheme = "ws"
+	if u.Host == "" {
+		u.Host = "localhost"
+		if b.ClientReq != nil && b.ClientReq.Host != "" {
+			u.Host = b.ClientReq.Host
+		}
+	}
+
+	header := make(http.Header)
+	if b.ClientReq != nil {
+		for k, values := range b.ClientReq.Header {
+			header[k] = append([]string{}, values...)
+		}
+
+		for _, k := range handshakeHeaders {
+			header.Del(k)
+		}
+	}
+
+	clientConn, serverConn := net.Pipe()
+	go b.serve(serverConn)
+
+	dialer := websocket.Dialer{
+		NetDialContext: func(context.Context, string, string) (net.Conn, error) {
+			return clientConn, nil
+		},
+	}
+
+	c, _, err := dialer.DialContext(ctx, u.String(), header)
+	if err != nil {
+		clientConn.Close()
+		return nil, err
+	}
+
+	return &conn{c}, nil
+}
+
+// serve reads the handshake request from the pipe and passes it to the server's handler
+func (b *ServerBackend) serve(nc net.Conn) {
+	br := bufio.NewReader(nc)
+	req, err := http.ReadRequest(br)
+	if err != nil {
+		nc.Close()
+		return
+	}
+
+	w := &hijackWriter{
+		ResponseRecorder: httptest.NewRecorder(),
+		conn:             nc,
+		rw:               bufio.NewReadWriter(br, bufio.NewWriter(nc)),
+	}
+
+	b.Server.ServeHTTP(w, req)
+	if w.hijacked {
+		return
+	}
+
+	// the handler has refused the upgrade
+	w.Result().Write(nc)
+	nc.Close()
+}
+
+// hijackWriter is a ResponseWriter that lets the handler take over the pipe
+type hijackWriter struct {
+	*httptest.ResponseRecorder
+	conn     net.Conn
+	rw       *bufio.ReadWriter
+	hijacked bool
+}
+
+func (w *hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return w.conn, w.rw, nil
+}
+
+type conn struct {
+	c *websocket.Conn
+}
+
+func (c *conn) ReadMessage() (ws.Message, error) {
+	mt, data, err := c.c.ReadMessage()
+	if err != nil {
+		return ws.Message{}, err
+	}
+
+	return ws.Message{
+		Data:   data,
+		Binary: mt == websocket.BinaryMessage,
+	}, nil
+}
+
+func (c *conn) WriteMessage(m ws.Message) error {
+	mt := websocket.TextMessage
+	if m.Binary {
+		mt = websocket.BinaryMessage
+	}
+
+	return c.c.WriteMessage(mt, m.Data)
+}
+
+func (c *conn) Close() error {
+	c.c.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+		time.Now().Add(time.Second))
+	return c.c.Close()
+}
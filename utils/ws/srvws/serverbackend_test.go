@@ -0,0 +1,64 @@
+package serverside
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/gowade/wade/utils/ws"
+)
+
+func echoHandler() http.Handler {
+	upgrader := websocket.Upgrader{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		c, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+
+		defer c.Close()
+		c.WriteMessage(websocket.TextMessage, []byte("hello "+r.Header.Get("X-User")))
+		for {
+			mt, data, err := c.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			c.WriteMessage(mt, data)
+		}
+	})
+
+	return mux
+}
+
+func TestServerBackend(t *testing.T) {
+	clientReq, _ := http.NewRequest("GET", "http://example.com/", nil)
+	clientReq.Header.Set("X-User", "bob")
+	b := &ServerBackend{
+		Server:    echoHandler(),
+		ClientReq: clientReq,
+	}
+
+	s := ws.NewSocket("/echo")
+	s.Driver = b
+	s.Connect(context.Background())
+	defer s.Close()
+
+	if m := <-s.Messages(); m.Text() != "hello bob" {
+		t.Fatalf("expected the greeting, got %q", m.Text())
+	}
+
+	if err := s.Send(ws.Message{Data: []byte{0, 1, 2}, Binary: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if m := <-s.Messages(); !m.Binary || len(m.Data) != 3 {
+		t.Fatalf("expected the binary message back, got %+v", m)
+	}
+
+	if _, err := b.Dial(context.Background(), "/missing"); err == nil {
+		t.Fatal("expected the handshake to fail for a route without WebSocket")
+	}
+}
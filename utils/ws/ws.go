@@ -0,0 +1,78 @@
+// Package ws provides WebSocket connections through a pluggable driver,
+// and a Socket that reconnects when its connection is lost
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+var (
+	driver Driver
+
+	// ErrClosed is returned when sending on a closed Socket
+	ErrClosed = errors.New("ws: socket closed")
+
+	// ErrConnected is returned when connecting a Socket a second time
+	ErrConnected = errors.New("ws: socket already connected")
+)
+
+func SetDriver(drv Driver) {
+	driver = drv
+}
+
+// Dial opens a connection through the driver
+func Dial(ctx context.Context, url string) (Conn, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return driver.Dial(ctx, url)
+}
+
+type (
+	// Message is a WebSocket message, a text message unless Binary is set
+	Message struct {
+		Data   []byte
+		Binary bool
+	}
+
+	// Conn is an open WebSocket connection
+	Conn interface {
+		// ReadMessage blocks until a message is received, it returns an error
+		// once the connection is closed
+		ReadMessage() (Message, error)
+		WriteMessage(Message) error
+		Close() error
+	}
+
+	// Driver opens connections, Dial must give up when ctx is done
+	Driver interface {
+		Dial(ctx context.Context, url string) (Conn, error)
+	}
+)
+
+// TextMessage returns a text message
+func TextMessage(s string) Message {
+	return Message{Data: []byte(s)}
+}
+
+// JSONMessage returns a text message with v encoded to JSON
+func JSONMessage(v interface{}) (Message, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{Data: data}, nil
+}
+
+func (m Message) Text() string {
+	return string(m.Data)
+}
+
+// JSON decodes the message's JSON data into v
+func (m Message) JSON(v interface{}) error {
+	return json.Unmarshal(m.Data, v)
+}
@@ -34,6 +34,7 @@ func DevMode() bool {
 
 func SetMode(appMode AppMode) {
 	mode = appMode
+	driver.SetDevMode(appMode == DevelopmentMode)
 }
 
 func Str(value interface{}) string {
@@ -77,6 +78,67 @@ func WrapEvt(handler func(dom.Event)) interface{} {
 	return dom.NewEventHandler(handler)
 }
 
+// WrapHandler wraps an event handler with a typed event parameter, e.g func(dom.KeyboardEvent),
+// so that it can be attached to an element. Other values are returned unchanged.
+// fuel uses it for the on* attributes of elements.
+// An event that doesn't implement the typed interface is given to the handler
+// with the zero value for the properties it lacks.
+func WrapHandler(handler interface{}) interface{} {
+	if dom.NewEventHandler == nil {
+		// no DOM driver on the server, handlers are not rendered
+		return handler
+	}
+
+	switch h := handler.(type) {
+	case func(dom.Event):
+		return dom.NewEventHandler(h)
+	case dom.EventHandler:
+		return dom.NewEventHandler(h)
+	case func(dom.MouseEvent):
+		return dom.NewEventHandler(func(evt dom.Event) {
+			e, ok := evt.(dom.MouseEvent)
+			if !ok {
+				e = typedEvent{evt}
+			}
+			h(e)
+		})
+	case func(dom.KeyboardEvent):
+		return dom.NewEventHandler(func(evt dom.Event) {
+			e, ok := evt.(dom.KeyboardEvent)
+			if !ok {
+				e = typedEvent{evt}
+			}
+			h(e)
+		})
+	case func(dom.InputEvent):
+		return dom.NewEventHandler(func(evt dom.Event) {
+			e, ok := evt.(dom.InputEvent)
+			if !ok {
+				e = typedEvent{evt}
+			}
+			h(e)
+		})
+	case func(dom.FocusEvent):
+		return dom.NewEventHandler(func(evt dom.Event) {
+			e, ok := evt.(dom.FocusEvent)
+			if !ok {
+				e = typedEvent{evt}
+			}
+			h(e)
+		})
+	case func(dom.SubmitEvent):
+		return dom.NewEventHandler(func(evt dom.Event) {
+			e, ok := evt.(dom.SubmitEvent)
+			if !ok {
+				e = typedEvent{evt}
+			}
+			h(e)
+		})
+	}
+
+	return handler
+}
+
 func QueryEscape(str string) string {
 	return gourl.QueryEscape(str)
 }
@@ -91,8 +153,13 @@ func NewVNodeList(nodes ...interface{}) []vdom.VNode {
 		switch n := n.(type) {
 		case []vdom.VNode:
 			l = append(l, n...)
-		case *vdom.VElement, vdom.VText:
-			l = append(l, n.(vdom.VNode))
+		case *vdom.VElement:
+			// a nil element renders nothing, like a nil node
+			if n != nil {
+				l = append(l, n)
+			}
+		case vdom.VText:
+			l = append(l, n)
 		default:
 			panic("Invalid node type")
 		}